@@ -0,0 +1,396 @@
+package beautify
+
+import (
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+	"github.com/go-xuan/sqlx/utils"
+)
+
+// AlterType 修改表动作类型
+type AlterType string
+
+const (
+	AddColumn      AlterType = "add column"      // 新增字段
+	DropColumn     AlterType = "drop column"     // 删除字段
+	ModifyColumn   AlterType = "modify column"   // 修改字段定义
+	ChangeColumn   AlterType = "change column"   // 修改字段名及定义
+	RenameColumn   AlterType = "rename column"   // 重命名字段
+	AlterColumn    AlterType = "alter column"    // 修改字段属性（类型、非空等）
+	SetDefault     AlterType = "set default"     // 设置字段默认值
+	DropDefault    AlterType = "drop default"    // 删除字段默认值
+	AddConstraint  AlterType = "add constraint"  // 新增约束
+	DropConstraint AlterType = "drop constraint" // 删除约束
+	AddIndex       AlterType = "add index"       // 新增索引
+	DropIndex      AlterType = "drop index"      // 删除索引
+	RenameIndex    AlterType = "rename index"    // 重命名索引
+	RenameTable    AlterType = "rename to"       // 重命名表
+	SetComment     AlterType = "comment"         // 设置表注释
+	AlterOther     AlterType = "other"           // 其他无法识别的动作，原文保留
+)
+
+// ParseAlterTableSQL 解析修改表SQL
+func ParseAlterTableSQL(sql string, indent ...int) *AlterTable {
+	// sql初始化
+	var parser = &AlterTable{
+		Base: NewBase(sql, indent...),
+	}
+
+	// sql解析
+	parser.parsePrepare() // 解析准备
+	parser.parseTable()   // 解析表名
+	parser.parseActions() // 解析修改动作
+	parser.parseFinish()  // 解析完成
+
+	return parser
+}
+
+type AlterTable struct {
+	Base
+	Table    *Table         // 修改表
+	IfExists bool           // 是否if exists
+	Only     bool           // 是否only（postgresql不作用于子表）
+	Actions  []*AlterAction // 修改动作，多个动作以逗号分隔
+}
+
+// AlterAction 修改表动作解析
+type AlterAction struct {
+	Type       AlterType   // 动作类型
+	Column     *Field      // 字段定义（add/modify/change column）
+	Constraint *Constraint // 约束（add/drop constraint）
+	Index      *Index      // 索引（add/drop/rename index）
+	Name       string      // 动作目标名称（字段名、约束名、索引名）
+	NewName    string      // 重命名之后的名称
+	Value      string      // 动作值（默认值、注释、字段属性）
+	Position   string      // 字段位置（first、after x）
+	Extra      string      // 其他选项原文（cascade、if exists等）
+}
+
+// Beautify SQL美化输出
+func (x *AlterTable) Beautify() string {
+	var sql = strings.Builder{}
+	sql.WriteString(consts.ALTER)
+	sql.WriteString(consts.Blank)
+	sql.WriteString(consts.TABLE)
+	sql.WriteString(consts.Blank)
+	if x.IfExists {
+		sql.WriteString("if exists ")
+	}
+	if x.Only {
+		sql.WriteString("only ")
+	}
+	sql.WriteString(x.Table.beautify())
+	for i, action := range x.Actions {
+		if i > 0 {
+			sql.WriteString(consts.Comma)
+		}
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(x.align())
+		sql.WriteString(action.beautify())
+	}
+	return x.finish(sql.String())
+}
+
+// 提取表名
+func (x *AlterTable) parseTable() *AlterTable {
+	// 引号内的表名可能包含空格（`order items`）
+	words := utils.SplitFieldsExcludeInBracket(x.tempSql)
+	var i = 2 // 跳过alter table
+	if i+1 < len(words) && strings.EqualFold(words[i], consts.IF) && strings.EqualFold(words[i+1], consts.EXISTS) {
+		x.IfExists = true
+		i += 2
+	}
+	if i < len(words) && strings.EqualFold(words[i], "only") {
+		x.Only = true
+		i++
+	}
+	if i >= len(words) {
+		panic("当前输入sql无法解析 " + x.originSql)
+	}
+	x.Table = NewTable(words[i])
+	x.tempSql = strings.Join(words[i+1:], consts.Blank)
+	return x
+}
+
+// 提取修改动作
+func (x *AlterTable) parseActions() *AlterTable {
+	sql := strings.TrimSuffix(strings.TrimSpace(x.tempSql), consts.Semicolon)
+	list, last := utils.SplitExcludeInBracket(sql, consts.Comma)
+	list = append(list, last)
+	for _, actionSql := range list {
+		if words := utils.SplitFieldsExcludeInBracket(actionSql); len(words) > 0 {
+			x.Actions = append(x.Actions, x.newAction(words))
+		}
+	}
+	return x
+}
+
+// 解析单个修改动作
+func (x *AlterTable) newAction(words []string) *AlterAction {
+	var action = &AlterAction{}
+	switch strings.ToLower(words[0]) {
+	case consts.ADD:
+		x.parseAdd(action, words[1:])
+	case consts.DROP:
+		x.parseDrop(action, words[1:])
+	case consts.MODIFY:
+		action.Type = ModifyColumn
+		words = skipWord(words[1:], consts.COLUMN)
+		words, action.Position = cutPosition(words)
+		action.Column = x.newColumn(words)
+	case consts.CHANGE:
+		action.Type = ChangeColumn
+		words = skipWord(words[1:], consts.COLUMN)
+		if len(words) > 1 {
+			words, action.Position = cutPosition(words)
+			action.Name, action.Column = words[0], x.newColumn(words[1:])
+		}
+	case consts.RENAME:
+		x.parseRename(action, words[1:])
+	case consts.ALTER:
+		x.parseAlterColumn(action, words[1:])
+	case consts.COMMENT:
+		action.Type = SetComment
+		value := strings.TrimPrefix(strings.Join(words[1:], consts.Blank), consts.EQ)
+		action.Value = x.restore(strings.TrimSpace(value))
+	default:
+		if word, value, ok := strings.Cut(words[0], consts.EQ); ok && strings.EqualFold(word, consts.COMMENT) {
+			action.Type, action.Value = SetComment, x.restore(value)
+		} else {
+			action.Type, action.Value = AlterOther, x.restore(strings.Join(words, consts.Blank))
+		}
+	}
+	return action
+}
+
+// add column / add constraint / add index
+func (x *AlterTable) parseAdd(action *AlterAction, words []string) {
+	if len(words) == 0 {
+		action.Type = AlterOther
+		return
+	}
+	switch strings.ToLower(words[0]) {
+	case consts.CONSTRAINT, consts.PRIMARY, consts.UNIQUE, consts.FOREIGN, consts.CHECK:
+		action.Type, action.Constraint = AddConstraint, x.newConstraint(words)
+		action.Name = action.Constraint.Name
+	case consts.KEY, consts.INDEX, consts.FULLTEXT, consts.SPATIAL:
+		action.Type, action.Index = AddIndex, x.newIndex(words)
+		action.Name = action.Index.Name
+	default:
+		action.Type = AddColumn
+		words = skipWord(words, consts.COLUMN)
+		if len(words) > 3 && strings.EqualFold(strings.Join(words[:3], consts.Blank), consts.IFNOTEXISTS) {
+			action.Extra, words = consts.IFNOTEXISTS, words[3:]
+		}
+		words, action.Position = cutPosition(words)
+		action.Column = x.newColumn(words)
+		action.Name = action.Column.Name
+	}
+}
+
+// drop column / drop constraint / drop index / drop default
+func (x *AlterTable) parseDrop(action *AlterAction, words []string) {
+	if len(words) == 0 {
+		action.Type = AlterOther
+		return
+	}
+	switch strings.ToLower(words[0]) {
+	case consts.CONSTRAINT, consts.CHECK:
+		action.Type = DropConstraint
+		words = words[1:]
+	case consts.PRIMARY:
+		action.Type = DropConstraint
+		action.Constraint = &Constraint{Type: consts.PRIMARYKEY}
+		words = skipWord(words[1:], consts.KEY)
+	case consts.FOREIGN:
+		action.Type = DropConstraint
+		action.Constraint = &Constraint{Type: consts.FOREIGNKEY}
+		words = skipWord(words[1:], consts.KEY)
+	case consts.INDEX, consts.KEY:
+		action.Type = DropIndex
+		words = words[1:]
+	case consts.DEFAULT:
+		action.Type = DropDefault
+		words = words[1:]
+	default:
+		action.Type = DropColumn
+		words = skipWord(words, consts.COLUMN)
+	}
+	if len(words) > 1 && strings.EqualFold(words[0], consts.IF) && strings.EqualFold(words[1], consts.EXISTS) {
+		action.Extra, words = consts.IF+consts.Blank+consts.EXISTS, words[2:]
+	}
+	if len(words) > 0 {
+		action.Name = words[0]
+		if action.Constraint != nil {
+			action.Constraint.Name = words[0]
+		}
+		if len(words) > 1 {
+			action.Extra = strings.TrimSpace(action.Extra + consts.Blank + strings.Join(words[1:], consts.Blank))
+		}
+	}
+}
+
+// rename column / rename index / rename to
+func (x *AlterTable) parseRename(action *AlterAction, words []string) {
+	if len(words) == 0 {
+		action.Type = AlterOther
+		return
+	}
+	switch strings.ToLower(words[0]) {
+	case consts.TO, consts.AS:
+		action.Type = RenameTable
+		if len(words) > 1 {
+			action.NewName = words[1]
+		}
+		return
+	case consts.INDEX, consts.KEY:
+		action.Type = RenameIndex
+		words = words[1:]
+	case consts.CONSTRAINT:
+		action.Type = AlterOther
+		action.Value = x.restore(consts.RENAME + consts.Blank + strings.Join(words, consts.Blank))
+		return
+	default:
+		if len(words) == 1 { // mysql：rename new_name
+			action.Type, action.NewName = RenameTable, words[0]
+			return
+		}
+		action.Type = RenameColumn
+		words = skipWord(words, consts.COLUMN)
+	}
+	if len(words) > 0 {
+		action.Name = words[0]
+	}
+	if len(words) > 2 && strings.EqualFold(words[1], consts.TO) {
+		action.NewName = words[2]
+	}
+}
+
+// alter column x set default / drop default / type / set not null
+func (x *AlterTable) parseAlterColumn(action *AlterAction, words []string) {
+	words = skipWord(words, consts.COLUMN)
+	if len(words) == 0 {
+		action.Type = AlterOther
+		return
+	}
+	action.Name, words = words[0], words[1:]
+	var value = strings.ToLower(strings.Join(words, consts.Blank))
+	switch {
+	case strings.HasPrefix(value, consts.SET+consts.Blank+consts.DEFAULT+consts.Blank):
+		action.Type = SetDefault
+		action.Value = x.restore(strings.Join(words[2:], consts.Blank))
+	case value == consts.DROP+consts.Blank+consts.DEFAULT:
+		action.Type = DropDefault
+	default:
+		action.Type = AlterColumn
+		action.Value = x.restore(strings.Join(words, consts.Blank))
+	}
+}
+
+func (a *AlterAction) beautify() string {
+	var sql = strings.Builder{}
+	switch a.Type {
+	case AddColumn, ModifyColumn:
+		sql.WriteString(string(a.Type))
+		if a.Extra != consts.Empty {
+			sql.WriteString(consts.Blank)
+			sql.WriteString(a.Extra)
+		}
+		sql.WriteString(consts.Blank)
+		sql.WriteString(a.Column.definition())
+	case ChangeColumn:
+		sql.WriteString(string(a.Type))
+		sql.WriteString(consts.Blank)
+		sql.WriteString(a.Name)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(a.Column.definition())
+	case AddConstraint:
+		sql.WriteString(consts.ADD)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(a.Constraint.beautify())
+	case AddIndex:
+		sql.WriteString(consts.ADD)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(a.Index.beautify())
+	case DropConstraint:
+		sql.WriteString(consts.DROP)
+		sql.WriteString(consts.Blank)
+		if a.Constraint != nil {
+			sql.WriteString(a.Constraint.Type)
+		} else {
+			sql.WriteString(consts.CONSTRAINT)
+		}
+	case DropColumn, DropIndex, RenameColumn, RenameIndex:
+		sql.WriteString(string(a.Type))
+	case SetDefault, DropDefault, AlterColumn:
+		sql.WriteString(string(AlterColumn))
+		sql.WriteString(consts.Blank)
+		sql.WriteString(a.Name)
+		sql.WriteString(consts.Blank)
+		if a.Type == AlterColumn {
+			sql.WriteString(a.Value)
+		} else if a.Type == SetDefault {
+			sql.WriteString(consts.SET)
+			sql.WriteString(consts.Blank)
+			sql.WriteString(consts.DEFAULT)
+			sql.WriteString(consts.Blank)
+			sql.WriteString(a.Value)
+		} else {
+			sql.WriteString(string(DropDefault))
+		}
+	case RenameTable:
+		sql.WriteString(string(a.Type))
+		sql.WriteString(consts.Blank)
+		sql.WriteString(a.NewName)
+	case SetComment:
+		sql.WriteString(consts.COMMENT)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(a.Value)
+	default:
+		sql.WriteString(a.Value)
+	}
+	switch a.Type {
+	case DropColumn, DropIndex, DropConstraint:
+		if a.Extra != consts.Empty && strings.HasPrefix(a.Extra, consts.IF) {
+			sql.WriteString(" if exists")
+		}
+		if a.Name != consts.Empty {
+			sql.WriteString(consts.Blank)
+			sql.WriteString(a.Name)
+		}
+		if extra := strings.TrimSpace(strings.TrimPrefix(a.Extra, consts.IF+consts.Blank+consts.EXISTS)); extra != consts.Empty {
+			sql.WriteString(consts.Blank)
+			sql.WriteString(extra)
+		}
+	case RenameColumn, RenameIndex:
+		sql.WriteString(consts.Blank)
+		sql.WriteString(a.Name)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.TO)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(a.NewName)
+	}
+	if a.Position != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(a.Position)
+	}
+	return sql.String()
+}
+
+// 跳过开头的指定关键字
+func skipWord(words []string, word string) []string {
+	if len(words) > 0 && strings.EqualFold(words[0], word) {
+		return words[1:]
+	}
+	return words
+}
+
+// 截取mysql字段位置（first、after x）
+func cutPosition(words []string) ([]string, string) {
+	if l := len(words); l > 1 && strings.EqualFold(words[l-1], consts.FIRST) {
+		return words[:l-1], consts.FIRST
+	} else if l > 2 && strings.EqualFold(words[l-2], consts.AFTER) {
+		return words[:l-2], consts.AFTER + consts.Blank + words[l-1]
+	}
+	return words, consts.Empty
+}
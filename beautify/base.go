@@ -17,25 +17,56 @@ func NewBase(sql string, indent ...int) Base {
 	if len(indent) > 0 { // 累加缩缩进
 		base.indent += indent[0]
 	}
+	base.parseIndent = base.indent
 	return base
 }
 
 // Base SQL解析器base
 type Base struct {
-	originSql string            // 原始sql，原始完整sql（变量值需要通过 replacer 进行还原）
-	tempSql   string            // 临时sql，存储每个步骤经过sql拆解之后的sql片段
-	indent    int               // 缩进量
-	simple    bool              // 简单sql
-	replacer  *strings.Replacer // 变量值替换器，consts.ReplacePrefix + 编号 + consts.ReplaceSuffix
+	originSql    string            // 原始sql，原始完整sql（变量值需要通过 replacer 进行还原）
+	tempSql      string            // 临时sql，存储每个步骤经过sql拆解之后的sql片段
+	indent       int               // 缩进量
+	simple       bool              // 简单sql
+	replacer     *strings.Replacer // 变量值替换器，consts.ReplacePrefix + 编号 + consts.ReplaceSuffix
+	placeholders []*Placeholder    // 绑定占位符
+	dialect      Dialect           // 数据库方言，未指定时为nil
+	Span         Span              // 语句在原始sql中的位置，仅Parse解析时定位
+	diagnostics  []*Diagnostic     // 容错模式下的诊断信息
+
+	formatOptions *FormatOptions // 格式化选项，未设置时为默认选项
+	parseIndent   int            // 解析时的缩进量，设置格式化选项时以此为基准重新计算缩进
+	root          bool           // 是否最外层语句（仅最外层语句转换大小写、制表符以及处理末尾分号）
+	nested        bool           // 是否嵌套的子查询（首行紧随左括号输出，不按对齐宽度补齐）
+	source        string         // 最外层语句的原始sql，保留大小写时参照其中的写法
+	input         string         // 传入Parse的原始sql（包含注释），转换占位符时在其中替换以保留注释
+}
+
+// Dialect 解析时指定的数据库方言，未指定时为nil
+func (b *Base) Dialect() Dialect {
+	return b.dialect
+}
+
+func (b *Base) setDialect(dialect Dialect) {
+	b.dialect = dialect
+}
+
+// 是否解析方言子句，未指定方言时解析除settings、format之外的全部方言子句（二者容易与同名字段混淆）
+func (b *Base) supports(clause Clause) bool {
+	if b.dialect == nil {
+		return clause != SettingsClause && clause != FormatClause
+	}
+	return b.dialect.Supports(clause)
 }
 
 // 解析准备
 func (b *Base) parsePrepare() {
 	sql := b.tempSql
+	// 解析sql中所有的绑定占位符，复杂的mybatis占位符需要替换，避免影响后续sql解析
+	sql, oldnew := b.parsePlaceholders(sql)
 	// 解析sql中所有的参数值，避免参数值值影响后续sql解析
-	var replacer *strings.Replacer
-	if sql, replacer = utils.ParseValuesInSql(sql); replacer != nil {
-		b.replacer = replacer
+	sql, values := utils.ExtractValuesInSql(sql)
+	if oldnew = append(oldnew, values...); len(oldnew) > 0 {
+		b.replacer = strings.NewReplacer(oldnew...)
 	}
 	// 将sql中所有关键字转为小写
 	b.tempSql = utils.AllKeywordsToLower(sql)
@@ -46,19 +77,27 @@ func (b *Base) parseFinish() {
 	b.tempSql = ""
 }
 
+// 还原sql片段中被替换的变量值
+func (b *Base) restore(sql string) string {
+	if b.replacer != nil && (utils.IndexOfString(sql, consts.ReplacePrefix) >= 0 || utils.IndexOfString(sql, consts.ParamPrefix) >= 0) {
+		return b.replacer.Replace(sql)
+	}
+	return sql
+}
+
 // 以当前缩进量对齐
 func (b *Base) align(key ...string) string {
 	return Align(b.indent, key...)
 }
 
-// Align 根据缩进量对齐
+// Align 根据缩进量对齐，按显示宽度计算（中文等宽字符占两列）
 func Align(indent int, key ...string) string {
 	if len(key) == 0 {
 		return strings.Repeat(consts.Blank, indent)
-	} else if str := key[0]; len(str) <= indent {
-		return strings.Repeat(consts.Blank, indent-len(str)) + str
-	} else if cut, _ := utils.CutString(str, consts.Blank); len(cut) <= indent {
-		return strings.Repeat(consts.Blank, indent-len(cut)) + str
+	} else if str := key[0]; utils.DisplayWidth(str) <= indent {
+		return strings.Repeat(consts.Blank, indent-utils.DisplayWidth(str)) + str
+	} else if cut, _ := utils.CutString(str, consts.Blank); utils.DisplayWidth(cut) <= indent {
+		return strings.Repeat(consts.Blank, indent-utils.DisplayWidth(cut)) + str
 	} else {
 		return str
 	}
@@ -66,39 +105,85 @@ func Align(indent int, key ...string) string {
 
 // ExtractWhere 提取条件
 func ExtractWhere(sql string) ([]*Condition, string) {
+	return extractWhere(sql, nil, defaultIndent)
+}
+
+// 按方言提取条件，indent为所在语句的缩进量
+func extractWhere(sql string, dialect Dialect, indent int) ([]*Condition, string) {
 	if sql != "" {
 		if index := utils.IndexOfKeywordFirst(sql, consts.WHERE); index >= 0 {
 			// 去除where关键字
 			sql = sql[index+5:]
 			// 提取where部分sql
 			var whereSql string
-			if _, end := utils.ContainsKeywords(sql, consts.GROUPBY, consts.ORDERBY, consts.LIMIT); end >= 0 {
+			if _, end := utils.ContainsKeywordsExcludeBrackets(sql, consts.GROUPBY, consts.ORDERBY, consts.LIMIT); end >= 0 {
 				whereSql, sql = sql[:end], sql[end:]
 			} else {
 				whereSql, sql = sql, consts.Empty
 			}
 			// 提取Conditions条件
-			return NewConditions(whereSql), sql
+			return newConditions(whereSql, dialect, indent), sql
 		}
 	}
 	return nil, sql
 }
 
+// 截取末尾的order by以及limit子句（mysql的delete、update），括号内子查询中的子句不截取
+func cutOrderByLimit(sql string) (string, []string, string) {
+	var orderBy []string
+	var limit string
+	bracket := utils.IndexOfString(sql, consts.RightBracket, -1)
+	if index := utils.IndexOfKeywordLast(sql, consts.LIMIT); index > 0 && index > bracket {
+		limit, sql = strings.TrimSpace(sql[index+6:]), sql[:index]
+	}
+	if index := utils.IndexOfKeywordLast(sql, consts.ORDERBY); index > 0 && index > bracket {
+		list, last := utils.SplitExcludeInBracket(sql[index+9:], consts.Comma)
+		for _, value := range append(list, last) {
+			orderBy = append(orderBy, strings.TrimSpace(value))
+		}
+		sql = sql[:index]
+	}
+	return strings.TrimSpace(sql), orderBy, limit
+}
+
+// 构建order by以及limit子句
+func (b *Base) beautifyOrderByLimit(orderBy []string, limit string) string {
+	var sql = strings.Builder{}
+	if len(orderBy) > 0 {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(b.align(consts.ORDERBY))
+		sql.WriteString(consts.Blank)
+		sql.WriteString(strings.Join(orderBy, ", "))
+	}
+	if limit != consts.Empty {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(b.align(consts.LIMIT))
+		sql.WriteString(consts.Blank)
+		sql.WriteString(limit)
+	}
+	return sql.String()
+}
+
 // NewConditions 全部条件
 func NewConditions(sql string) []*Condition {
+	return newConditions(sql, nil, defaultIndent)
+}
+
+// 按方言解析全部条件，indent为所在语句的缩进量
+func newConditions(sql string, dialect Dialect, indent int) []*Condition {
 	// 去除前后多余括号
 	sql = utils.TrimBrackets(sql)
 	var conditions []*Condition
 	var loop, andOr = true, ""
 	for loop {
 		if index := utils.IndexExcludeBrackets(sql, consts.AND, true); index > 0 {
-			conditions = append(conditions, NewCondition(sql[:index], andOr))
+			conditions = append(conditions, newCondition(sql[:index], andOr, dialect, indent))
 			sql, andOr = sql[index+4:], consts.AND
 		} else if index = utils.IndexExcludeBrackets(sql, consts.OR, true); index > 0 {
-			conditions = append(conditions, NewCondition(sql[:index], andOr))
+			conditions = append(conditions, newCondition(sql[:index], andOr, dialect, indent))
 			sql, andOr = sql[index+3:], consts.OR
 		} else {
-			conditions = append(conditions, NewCondition(sql, andOr))
+			conditions = append(conditions, newCondition(sql, andOr, dialect, indent))
 			loop = false
 		}
 	}
@@ -107,12 +192,19 @@ func NewConditions(sql string) []*Condition {
 
 // NewCondition 单个条件
 func NewCondition(sql string, andOr string) *Condition {
+	return newCondition(sql, andOr, nil, defaultIndent)
+}
+
+// 按方言解析单个条件，indent为所在语句的缩进量
+func newCondition(sql string, andOr string, dialect Dialect, indent int) *Condition {
 	// 去除前后空格
 	sql = strings.TrimSpace(sql)
-	var condition = &Condition{AndOr: andOr}
+	var condition = &Condition{AndOr: andOr, dialect: dialect, indent: indent}
 	if from, to := utils.BetweenOfString(sql, consts.LeftBracket, consts.RightBracket); from == 0 && to == len(sql)-1 {
-		condition.Conditions = NewConditions(sql[from+1 : to]) // ()括号在前后两端表示是联合子条件
-	} else if index := utils.IndexExcludeBrackets(sql, consts.NE, true); index > 0 {
+		condition.Conditions = newConditions(sql[from+1:to], dialect, indent) // ()括号在前后两端表示是联合子条件
+	} else if index := utils.IndexExcludeBrackets(strings.ToLower(sql), "distinct from", true); index > 0 && condition.parseDialectOperator(sql) {
+		// postgresql：is [not] distinct from 需要优先于is not匹配
+	} else if index = utils.IndexExcludeBrackets(sql, consts.NE, true); index > 0 {
 		condition.Name = sql[:index-1]
 		condition.Operator = sql[index : index+2]
 		condition.Value = sql[index+3:]
@@ -156,17 +248,45 @@ func NewCondition(sql string, andOr string) *Condition {
 		condition.Name = sql[:index-1]
 		condition.Operator = sql[index : index+2]
 		condition.Value = sql[index+3:]
-	} else {
+	} else if !condition.parseDialectOperator(sql) {
 		condition.Name = sql
 	}
+	condition.parseOuterJoin()
+	condition.parseGlobal()
+	// 解析条件两侧的字面量、类型转换以及排序规则
+	if expr := NewExpression(condition.Name); expr != nil {
+		condition.NameExpr, condition.Name = expr, expr.beautify()
+	}
+	if expr := NewExpression(condition.Value); expr != nil {
+		condition.ValueExpr, condition.Value = expr, expr.beautify()
+	}
 	return condition
 }
 
+// 解析方言运算符条件（postgresql：ilike、@>、?，mysql：<=>、regexp等），仅匹配当前方言的运算符，无匹配时返回false
+func (c *Condition) parseDialectOperator(sql string) bool {
+	var operators = genericOperators
+	if c.dialect != nil {
+		operators = c.dialect.Operators()
+	}
+	lower := strings.ToLower(sql)
+	for _, operator := range operators {
+		if index := utils.IndexExcludeBrackets(lower, operator, true); index > 0 {
+			c.Name = strings.TrimSpace(sql[:index])
+			c.Operator = operator
+			c.Value = strings.TrimSpace(sql[index+len(operator):])
+			return true
+		}
+	}
+	return false
+}
+
 // Join 关联表解析
 type Join struct {
 	Table *Table // join表对象
 	Type  string // join类型left/right/inner
 	On    string // 关联条件
+	Span  Span   // 原始sql中的位置
 }
 
 // Condition 查询条件解析
@@ -178,16 +298,41 @@ type Condition struct {
 	Values     []string     // in值
 	Select     *Select      // 子查询
 	Conditions []*Condition // 子条件
+	NameExpr   *Expression  // 字段表达式解析（字面量、类型转换、排序规则）
+	ValueExpr  *Expression  // 值表达式解析（字面量、类型转换、排序规则）
+	OuterJoin  string       // oracle旧式外连接：(+)在值一侧为left，在字段一侧为right
+	Global     bool         // clickhouse：global in、global not in
+	Span       Span         // 原始sql中的位置
+	Bad        *BadExpr     // 容错模式下无法解析的条件，美化时原样输出
+
+	format  *FormatOptions // 格式化选项，未设置时为默认选项
+	dialect Dialect        // 数据库方言，未指定时为nil
+	indent  int            // 所在语句的缩进量
 }
 
 func (c *Condition) parseIn(sql string) {
 	sql = strings.Trim(sql, "() ;")
 	if index := utils.IndexOfKeywordFirst(sql, consts.SELECT); index >= 0 {
-		indent := len(c.Name) + 12
-		c.Select = ParseSelectSQL(sql, indent)
+		// 子查询紧随左括号（where a in (select），按所在语句的缩进量以及字段、运算符的宽度对齐
+		indent := c.indent - defaultIndent + utils.DisplayWidth(c.Name) + len(c.Operator) + 10
+		c.Select = parseSelect(sql, c.dialect, indent)
 	} else {
-		c.Values = strings.Split(sql, consts.Comma)
+		list, last := utils.SplitExcludeInBracket(sql, consts.Comma)
+		for _, value := range append(list, last) {
+			if value = strings.TrimSpace(value); NewCast(value) != nil {
+				value = NewCast(value).beautify()
+			}
+			c.Values = append(c.Values, value)
+		}
+	}
+}
+
+// in列表换行数量
+func (c *Condition) inWrap() int {
+	if c.format == nil {
+		return defaultInWrap
 	}
+	return c.format.InWrap
 }
 
 func (c *Condition) beautify(indent int) string {
@@ -197,6 +342,10 @@ func (c *Condition) beautify(indent int) string {
 		sql.WriteString(Align(indent, c.AndOr))
 		sql.WriteString(consts.Blank)
 	}
+	if c.Bad != nil { // 无法解析的条件原样输出
+		sql.WriteString(c.Bad.Text)
+		return strings.TrimRight(sql.String(), consts.Blank)
+	}
 	if len(c.Conditions) > 0 { // 联合子条件
 		sql.WriteString("(")
 		for i, condition := range c.Conditions {
@@ -207,15 +356,22 @@ func (c *Condition) beautify(indent int) string {
 		}
 		sql.WriteString(")")
 	} else { // 单条件
-		indent = indent + len(c.Name) + 6
+		indent = indent + utils.DisplayWidth(c.Name) + 6
 		sql.WriteString(c.Name)
+		if c.OuterJoin == consts.RIGHT {
+			sql.WriteString(outerJoinMark)
+		}
 		sql.WriteString(consts.Blank)
+		if c.Global {
+			sql.WriteString(consts.GLOBAL)
+			sql.WriteString(consts.Blank)
+		}
 		sql.WriteString(c.Operator)
 		sql.WriteString(consts.Blank)
 		if c.Operator == consts.IN || c.Operator == consts.NOTIN {
 			sql.WriteString(consts.LeftBracket)
 			if len(c.Values) > 0 {
-				var nextLine = len(c.Values) > 3
+				var nextLine = len(c.Values) > c.inWrap()
 				for i, value := range c.Values {
 					if i > 0 {
 						sql.WriteString(consts.Comma)
@@ -233,6 +389,9 @@ func (c *Condition) beautify(indent int) string {
 			sql.WriteString(consts.RightBracket)
 		} else {
 			sql.WriteString(c.Value)
+			if c.OuterJoin == consts.LEFT {
+				sql.WriteString(outerJoinMark)
+			}
 		}
 	}
 	return sql.String()
@@ -240,6 +399,11 @@ func (c *Condition) beautify(indent int) string {
 
 // ExtractTable 提取主表
 func ExtractTable(sql string, indent int) (*Table, string) {
+	return extractTable(sql, indent, nil)
+}
+
+// 按方言提取主表
+func extractTable(sql string, indent int, dialect Dialect) (*Table, string) {
 	if index := utils.IndexExcludeBrackets(sql, consts.FROM, true); index >= 0 {
 		sql = sql[index+4:] // 截取掉from，但是保留表名前面的空格
 	} else if sql[:1] != consts.Blank {
@@ -248,37 +412,132 @@ func ExtractTable(sql string, indent int) (*Table, string) {
 	var table = &Table{}
 	if sql[1:2] == consts.LeftBracket { // 如果from后面跟括号，表示是子查询
 		if from, to := utils.BetweenOfString(sql, consts.LeftBracket, consts.RightBracket); from >= 0 && from < to {
-			table.Select = ParseSelectSQL(sql[from+1:to], indent+2)
+			table.Select = parseSelect(sql[from+1:to], dialect, indent+2)
 			sql = sql[to:]
 		} else {
 			panic("解析sql异常")
 		}
 	} else { // from后面直接跟表名
-		before := utils.IndexOfString(sql, consts.Blank, 1)                 // 表名前空格下标，前面已经做了处理，所以此空格必定存在
-		if after := utils.IndexOfString(sql, consts.Blank, 2); after >= 0 { // 表名后空格下标
-			table.Name, sql = sql[before+1:after], sql[after+1:]
+		// 表名前空格前面已经做了处理，所以此空格必定存在，表名后空格需要排除引号内的空格（"order items"）
+		after := utils.IndexExcludeQuotes(sql, consts.Blank, 1)
+		if after >= 0 && strings.Count(sql[:after], consts.LeftBracket) != strings.Count(sql[:after], consts.RightBracket) {
+			after = utils.IndexExcludeBrackets(sql[1:], consts.Blank, false) + 1 // 表值函数（string_split(t.tags, ',')）参数内可能包含空格
+		}
+		var name string
+		if after > 0 {
+			name, sql = sql[1:after], sql[after+1:]
 		} else {
-			table.Name, sql = sql[before+1:], ""
+			name, sql = sql[1:], ""
+		}
+		// 无别名的逗号分隔多表（from a, b、from a,b）
+		if index := utils.IndexExcludeQuotes(name, consts.Comma, 0); index > 0 && strings.Count(name[:index], consts.LeftBracket) == strings.Count(name[:index], consts.RightBracket) {
+			name, sql = name[:index], name[index:]+consts.Blank+sql
 		}
+		table = NewTable(name)
 	}
 	if sql != "" {
 		var alias string
-		if _, index := utils.ContainsKeywords(sql, consts.LEFT, consts.RIGHT, consts.INNER, consts.OUTER, consts.JOIN, consts.WHERE, consts.GROUPBY, consts.ORDERBY, consts.LIMIT); index >= 0 {
+		if _, index := utils.ContainsKeywords(sql, consts.LEFT, consts.RIGHT, consts.INNER, consts.OUTER, consts.CROSS, consts.JOIN, consts.STRAIGHTJOIN,
+			consts.GLOBAL, consts.ANY, consts.ALL, "full", "natural", "asof", consts.WHERE, consts.GROUPBY, consts.ORDERBY, consts.LIMIT); index >= 0 {
 			// 判断是否是复杂查询
 			alias, sql = sql[:index], sql[index:]
 		} else { // 简单查询
 			alias, sql = sql, consts.Empty
 		}
+		if index := utils.IndexExcludeBrackets(alias+consts.Blank, consts.Comma, false); index >= 0 { // 逗号分隔的多表（from a, b）
+			alias, sql = alias[:index], alias[index:]+sql
+		}
 		table.Alias = utils.ExtractAlias(alias)
 	}
 	return table, sql
 }
 
+// 提取表提示（mysql：force index (idx)，sql server：with (nolock)），方言不支持表提示时原样返回
+func (b *Base) extractTableHints(sql string, hints map[string][]string) string {
+	if b.supports(TableHintClause) {
+		return extractWithHints(extractIndexHints(sql, hints), hints)
+	}
+	return sql
+}
+
+// 记录表提示，按提示前紧邻的单词（表名或别名）归类
+func addTableHint(hints map[string][]string, prefix, hint string) {
+	if words := strings.Fields(prefix); len(words) > 0 {
+		key := words[len(words)-1]
+		hints[key] = append(hints[key], hint)
+	}
+}
+
+// 为表关联表提示，优先按别名匹配
+func attachTableHints(table *Table, hints map[string][]string) {
+	if table == nil || len(hints) == 0 {
+		return
+	}
+	if list, ok := hints[table.Alias]; ok && table.Alias != consts.Empty {
+		table.Hints = list
+	} else if list, ok = hints[table.FullName()]; ok {
+		table.Hints = list
+	}
+}
+
+// 规范表提示参数：(a,b) -> (a, b)
+func joinHintArgs(sql string) string {
+	var args []string
+	list, last := utils.SplitExcludeInBracket(sql, consts.Comma)
+	for _, arg := range append(list, last) {
+		if arg = strings.TrimSpace(arg); arg != consts.Empty {
+			args = append(args, arg)
+		}
+	}
+	return consts.LeftBracket + strings.Join(args, ", ") + consts.RightBracket
+}
+
+// NewTable 根据表名初始化表，支持限定表名（catalog.schema.table）以及引号标识符（`t`、"t"、[t]）
+func NewTable(name string) *Table {
+	var table = &Table{}
+	if parts := utils.SplitIdentifier(name); len(parts) > 0 {
+		table.Quote, table.quoted, parts = unquoteParts(parts)
+		switch l := len(parts); {
+		case l >= 3:
+			table.catalogs = parts[:l-2]
+			table.Catalog, table.Schema = strings.Join(table.catalogs, consts.Dot), parts[l-2]
+		case l == 2:
+			table.Schema = parts[0]
+		}
+		table.Name = parts[len(parts)-1]
+	} else {
+		table.Name = name
+	}
+	return table
+}
+
 // Table 主表解析
 type Table struct {
-	Name   string  // 表名
-	Alias  string  // 表别名
-	Select *Select // 子查询
+	Catalog string   // 库名（sql server：catalog.schema.table）
+	Schema  string   // 模式名（mysql为库名）
+	Name    string   // 表名，不包含引号
+	Alias   string   // 表别名
+	Quote   string   // 标识符引号（`、"、[），为空表示未引用
+	Select  *Select  // 子查询
+	Hints   []string // 表提示（mysql：force index (idx)，sql server：with (nolock)）
+	Sample  string   // 表采样（hive：tablesample (bucket 1 out of 4 on id)，clickhouse：sample 0.1）
+	Final   bool     // clickhouse：final
+	Span    Span     // 原始sql中的位置
+
+	quoted   uint     // 原文中被引用的部分，从表名开始按位记录，为0时引用全部
+	catalogs []string // 库名的各部分，引号内的库名可能包含点号，不能按点号重新拆分
+}
+
+// FullName 完整表名，包含库名、模式名以及引号
+func (p *Table) FullName() string {
+	var parts []string
+	if p.Catalog != consts.Empty {
+		parts = splitQualifier(p.Catalog, p.catalogs)
+	}
+	if p.Schema != consts.Empty {
+		parts = append(parts, p.Schema)
+	}
+	return joinIdentifier(p.Quote, p.quoted, append(parts, p.Name)...)
 }
 
 func (p *Table) beautify(withAs ...bool) string {
@@ -288,7 +547,12 @@ func (p *Table) beautify(withAs ...bool) string {
 		sql.WriteString(p.Select.Beautify())
 		sql.WriteString(consts.RightBracket)
 	} else {
-		sql.WriteString(p.Name)
+		sql.WriteString(p.FullName())
+	}
+	var tableSample = strings.HasPrefix(p.Sample, "tablesample")
+	if tableSample {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(p.Sample)
 	}
 	if p.Alias != "" {
 		if len(withAs) > 0 && withAs[0] {
@@ -298,20 +562,103 @@ func (p *Table) beautify(withAs ...bool) string {
 		sql.WriteString(consts.Blank)
 		sql.WriteString(p.Alias)
 	}
+	if p.Final {
+		sql.WriteString(" final")
+	}
+	if p.Sample != consts.Empty && !tableSample { // clickhouse采样位于别名以及final之后
+		sql.WriteString(consts.Blank)
+		sql.WriteString(p.Sample)
+	}
+	for _, hint := range p.Hints {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(hint)
+	}
 	return sql.String()
 
 }
 
 // Field 字段解析
 type Field struct {
-	Name      string // 字段名
-	Alias     string // 字段别名，仅查询使用
-	Table     string // 表名
-	Value     string // 字段值
-	Type      string // 字段类型
-	Precision int    // 长度
-	Scale     int    // 小数点
-	Nullable  bool   // 允许为空
-	Default   string // 默认值
-	Comment   string // 注释
+	Name          string      // 字段名
+	Alias         string      // 字段别名，仅查询使用
+	Table         string      // 字段所属表名或表别名，不包含引号
+	Quote         string      // 标识符引号（`、"、[），为空表示未引用
+	Value         string      // 字段值
+	Type          string      // 字段类型，建表时为完整类型，例如varchar(64)、int(11) unsigned
+	Precision     int         // 长度
+	Scale         int         // 小数点
+	Nullable      bool        // 允许为空
+	Default       string      // 默认值
+	Comment       string      // 注释
+	PrimaryKey    bool        // 列级主键，仅建表使用
+	Unique        bool        // 列级唯一，仅建表使用
+	AutoIncrement bool        // 自增，仅建表使用
+	Extra         string      // 其他列约束原文（collate、references、on update等），仅建表使用
+	Expr          *Expression // 字段表达式解析（字面量、类型转换、排序规则），仅查询使用
+	Span          Span        // 原始sql中的位置，仅查询、插入以及更新字段
+	Bad           *BadExpr    // 容错模式下无法解析的字段
+
+	incrementKeyword string   // 自增关键字原文（auto_increment、autoincrement）
+	quoted           uint     // 原文中被引用的部分，从字段名开始按位记录，为0时引用全部
+	qualifiers       []string // 字段所属表名的各部分，引号内的表名可能包含点号，不能按点号重新拆分
+}
+
+// NewColumn 根据字段引用初始化字段，支持限定字段（t.id、s.t.id）以及引号标识符，非字段引用（表达式、函数）原样作为字段名
+func NewColumn(sql string) *Field {
+	var field = &Field{}
+	if parts := utils.SplitIdentifier(sql); len(parts) > 0 {
+		field.Quote, field.quoted, parts = unquoteParts(parts)
+		field.qualifiers, field.Name = parts[:len(parts)-1], parts[len(parts)-1]
+		field.Table = strings.Join(field.qualifiers, consts.Dot)
+	} else if field.Expr = NewExpression(sql); field.Expr != nil {
+		field.Name = field.Expr.beautify()
+	} else {
+		field.Name = sql
+	}
+	return field
+}
+
+// 字段引用，包含表名以及引号
+func (f *Field) column() string {
+	if f.Table == consts.Empty {
+		return joinIdentifier(f.Quote, f.quoted, f.Name)
+	}
+	return joinIdentifier(f.Quote, f.quoted, append(splitQualifier(f.Table, f.qualifiers), f.Name)...)
+}
+
+// 限定名的各部分，优先使用解析时记录的各部分，限定名被修改过时按点号拆分
+func splitQualifier(qualifier string, parts []string) []string {
+	if len(parts) > 0 && strings.Join(parts, consts.Dot) == qualifier {
+		return append([]string{}, parts...)
+	}
+	return strings.Split(qualifier, consts.Dot)
+}
+
+// 去除限定标识符各部分的引号，返回引号、被引用的部分（从最后一部分开始按位记录）以及去除引号后的各部分
+func unquoteParts(parts []string) (string, uint, []string) {
+	var quote string
+	var quoted uint
+	var names = make([]string, len(parts))
+	for i, part := range parts {
+		var q string
+		if names[i], q = utils.UnquoteIdentifier(part); q != consts.Empty {
+			quote, quoted = q, quoted|1<<(len(parts)-1-i)
+		}
+	}
+	return quote, quoted, names
+}
+
+// 拼接限定标识符，仅引用原文中被引用的部分
+func joinIdentifier(quote string, quoted uint, parts ...string) string {
+	var names []string
+	for i, part := range parts {
+		if part == consts.Empty {
+			continue
+		} else if part == "*" || quoted != 0 && quoted&(1<<(len(parts)-1-i)) == 0 {
+			names = append(names, part)
+		} else {
+			names = append(names, utils.QuoteIdentifier(part, quote))
+		}
+	}
+	return strings.Join(names, consts.Dot)
 }
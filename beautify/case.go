@@ -0,0 +1,144 @@
+package beautify
+
+import (
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+	"github.com/go-xuan/sqlx/utils"
+)
+
+// CaseStyle 大小写风格
+type CaseStyle string
+
+const (
+	CaseDefault  CaseStyle = ""         // 默认：不转换，保持解析后的输出
+	CaseUpper    CaseStyle = "upper"    // 转为大写
+	CaseLower    CaseStyle = "lower"    // 转为小写
+	CasePreserve CaseStyle = "preserve" // 保留原始sql中的写法
+)
+
+// 非保留关键字以及常用数据类型，仅用于大小写转换（保留关键字取自方言）
+var caseKeywords = map[string]bool{
+	"if": true, "temporary": true, "procedure": true, "function": true, "trigger": true, "returns": true, "return": true,
+	"begin": true, "declare": true, "for": true, "each": true, "row": true, "before": true, "after": true, "instead": true,
+	"of": true, "cascade": true, "restrict": true, "add": true, "rename": true, "to": true, "modify": true, "nulls": true,
+	"first": true, "last": true, "rows": true, "range": true, "unbounded": true, "preceding": true, "following": true,
+	"current": true, "recursive": true, "materialized": true, "database": true, "schema": true, "sequence": true, "commit": true,
+	"transaction": true, "savepoint": true, "rollback": true, "interval": true, "collate": true, "escape": true, "matched": true,
+	"tables": true, "columns": true, "within": true, "lateral": true, "limit": true, "offset": true, "fetch": true, "next": true,
+	"only": true, "returning": true, "conflict": true, "do": true, "nothing": true, "show": true, "explain": true, "describe": true,
+	"use": true, "int": true, "integer": true, "bigint": true, "smallint": true, "tinyint": true, "decimal": true, "numeric": true,
+	"float": true, "double": true, "real": true, "char": true, "varchar": true, "nvarchar": true, "varchar2": true, "number": true,
+	"boolean": true, "bool": true, "text": true, "blob": true, "clob": true, "date": true, "datetime": true, "timestamp": true,
+	"time": true, "unsigned": true, "zerofill": true, "signed": true, "generated": true, "always": true, "stored": true,
+	"virtual": true, "identity": true, "current_timestamp": true,
+}
+
+// DDL语句中的关键字（建表、修改表、索引、视图、函数以及触发器），同样仅用于大小写转换
+var ddlKeywords = map[string]bool{
+	consts.TEMPORARY: true, consts.IF: true, consts.EXISTS: true, consts.FULLTEXT: true, consts.SPATIAL: true, consts.NULL: true,
+	consts.COMMENT: true, consts.AUTOINCREMENT: true, consts.AUTOINCREMENTSQLITE: true, consts.ENGINE: true, consts.CHARSET: true,
+	consts.CHARACTER: true, consts.COLLATE: true, consts.USING: true, consts.ADD: true, consts.DROP: true, consts.MODIFY: true,
+	consts.CHANGE: true, consts.RENAME: true, consts.COLUMN: true, consts.TO: true, consts.FIRST: true, consts.AFTER: true,
+	consts.CASCADE: true, consts.RESTRICT: true, consts.CONCURRENTLY: true, consts.MATERIALIZED: true, consts.FUNCTION: true,
+	consts.PROCEDURE: true, consts.TRIGGER: true, consts.RETURNS: true, consts.LANGUAGE: true, consts.BEGIN: true,
+	consts.BEFORE: true, consts.INSTEAD: true, consts.EACH: true, consts.EXECUTE: true, consts.OF: true,
+}
+
+// 大小写转换器
+type caser struct {
+	format    *FormatOptions
+	dialects  []Dialect         // 识别关键字的方言，未指定方言时为全部内置方言
+	functions map[string]string // 内置函数，小写名称对应函数名
+	words     map[string]bool   // 原始sql中的单词
+	spelling  map[string]string // 原始sql中单词小写对应的首个写法
+	names     map[string]bool   // 子句参数中区分大小写的名称（clickhouse输出格式：format JSONEachRow），保持原样
+	upper     int               // 原始sql中大写关键字数量减去小写关键字数量，美化时补充的关键字（as）按多数写法
+}
+
+// 按大小写风格转换sql中的关键字、内置函数以及未引用的标识符，引号内的标识符以及字面量保持不变
+func (f *FormatOptions) convertCase(sql, source string, dialect Dialect) string {
+	if f.KeywordCase == CaseDefault && f.FunctionCase == CaseDefault && f.IdentifierCase == CaseDefault {
+		return sql
+	}
+	var c = &caser{format: f, dialects: Dialects(), functions: map[string]string{}, words: map[string]bool{}, spelling: map[string]string{}, names: map[string]bool{}}
+	if match := formatPattern.FindStringSubmatch(sql); match != nil {
+		c.names[match[1]] = true
+	}
+	if dialect != nil {
+		c.dialects = []Dialect{dialect}
+	}
+	for _, d := range c.dialects {
+		for _, function := range d.Functions() {
+			if lower := strings.ToLower(function); c.functions[lower] == "" {
+				c.functions[lower] = function
+			}
+		}
+	}
+	utils.ConvertWords(source, func(word string, qualified, call bool) string {
+		var lower = strings.ToLower(word)
+		if !c.words[word] {
+			c.words[word] = true
+			if _, ok := c.spelling[lower]; !ok {
+				c.spelling[lower] = word
+			}
+		}
+		if !qualified && !call && c.isKeyword(lower) {
+			if word == lower {
+				c.upper--
+			} else if word == strings.ToUpper(word) {
+				c.upper++
+			}
+		}
+		return word
+	})
+	return utils.ConvertWords(sql, c.convert)
+}
+
+func (c *caser) convert(word string, qualified, call bool) string {
+	var lower = strings.ToLower(word)
+	switch {
+	case c.names[word]:
+		return word
+	case qualified: // 限定名中的单词均为标识符
+		return c.apply(word, c.format.IdentifierCase)
+	case call && c.functions[lower] != "":
+		if name := c.functions[lower]; name != lower && c.format.FunctionCase != CaseDefault {
+			return name // 区分大小写的函数只能使用原始名称
+		}
+		return c.apply(word, c.format.FunctionCase)
+	case c.isKeyword(lower):
+		if _, ok := c.spelling[lower]; !ok && c.format.KeywordCase == CasePreserve && c.upper > 0 {
+			return strings.ToUpper(word)
+		}
+		return c.apply(word, c.format.KeywordCase)
+	default:
+		return c.apply(word, c.format.IdentifierCase)
+	}
+}
+
+func (c *caser) isKeyword(word string) bool {
+	if caseKeywords[word] || ddlKeywords[word] {
+		return true
+	}
+	for _, dialect := range c.dialects {
+		if dialect.IsKeyword(word) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *caser) apply(word string, style CaseStyle) string {
+	switch style {
+	case CaseUpper:
+		return strings.ToUpper(word)
+	case CaseLower:
+		return strings.ToLower(word)
+	case CasePreserve:
+		if spelling, ok := c.spelling[strings.ToLower(word)]; ok && !c.words[word] {
+			return spelling
+		}
+	}
+	return word
+}
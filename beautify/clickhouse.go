@@ -0,0 +1,302 @@
+package beautify
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+	"github.com/go-xuan/sqlx/utils"
+)
+
+var (
+	formatPattern      = regexp.MustCompile(`(?i)\sformat\s+(` + formatName + `)\s*;?\s*$`)              // format JSONEachRow
+	settingsPattern    = regexp.MustCompile(`(?i)\ssettings\s+\w+\s*=`)                                  // settings max_threads = 8
+	settingPattern     = regexp.MustCompile(`^\s*(\w+)\s*=\s*([^\s=]+)\s*$`)                             // max_threads = 8
+	finalPattern       = regexp.MustCompile(`(?i)\sfinal(\s|$)`)                                         // from t final
+	samplePattern      = regexp.MustCompile(`(?i)\ssample\s+([\d./]+)(\s+offset\s+([\d./]+))?(\s|$)`)    // sample 0.1 offset 0.5
+	arrayJoinPattern   = regexp.MustCompile(`(?i)\s(left\s+)?array\s+join\s`)                            // [left] array join arr as a
+	groupByWithPattern = regexp.MustCompile(`(?i)\swith\s+(totals|rollup|cube)(\s|$)`)                   // group by a with totals
+	limitByPattern     = regexp.MustCompile(`(?i)^(\S+(?:\s*,\s*\S+)?|\S+\s+offset\s+\S+)\s+by\s+(.+)$`) // limit 5 by domain
+)
+
+// clickhouse条件运算符，按顺序匹配
+var clickhouseOperators = []string{"not ilike", "ilike"}
+
+// clickhouse输出格式名称，需要区分大小写以免与同名字段混淆（order by format desc）：
+// 首字母大写且包含小写字母（JSONEachRow、TabSeparated）或者常见的全大写格式（CSV、TSV）
+const formatName = `(?-i:[A-Z]\w*[a-z]\w*|CSV|TSV|JSON|XML|ORC|TSKV)`
+
+// ArrayJoin clickhouse数组展开：[left] array join arr as a, b
+type ArrayJoin struct {
+	Left   bool     // 是否left array join
+	Fields []string // 展开的数组表达式（包含别名）
+}
+
+func (a *ArrayJoin) beautify() string {
+	var sql = strings.Builder{}
+	if a.Left {
+		sql.WriteString(consts.LEFT)
+		sql.WriteString(consts.Blank)
+	}
+	sql.WriteString("array join ")
+	sql.WriteString(strings.Join(a.Fields, ", "))
+	return sql.String()
+}
+
+// LimitBy clickhouse分组限数：limit 5 by domain
+type LimitBy struct {
+	Limit   string   // 限数（5、5 offset 1、1, 5）
+	Columns []string // 分组表达式
+}
+
+// 提取settings以及format（settings max_threads = 8 format JSONEachRow），均位于查询末尾
+func (x *Select) parseSettings() *Select {
+	sql := x.tempSql
+	if match := formatPattern.FindStringSubmatchIndex(sql); x.supports(FormatClause) && match != nil && match[0] > utils.IndexOfString(sql, consts.RightBracket, -1) {
+		x.Format, sql = sql[match[2]:match[3]], sql[:match[0]]
+	}
+	if x.supports(SettingsClause) {
+		matches := settingsPattern.FindAllStringIndex(sql, -1)
+		for i := len(matches) - 1; i >= 0; i-- { // 取当前查询层级中最后一个settings
+			index := matches[i][0]
+			if strings.Count(sql[:index], consts.LeftBracket) != strings.Count(sql[:index], consts.RightBracket) {
+				continue
+			}
+			if settings := newSettings(sql[index+10:]); settings != nil {
+				x.Settings, sql = settings, sql[:index]
+			}
+			break
+		}
+	}
+	x.tempSql = sql
+	return x
+}
+
+// 解析查询设置，每一项均需为name = value形式，否则视为非settings子句并返回nil
+func newSettings(sql string) []*Field {
+	var settings []*Field
+	list, last := utils.SplitExcludeInBracket(sql, consts.Comma)
+	for _, item := range append(list, last) {
+		match := settingPattern.FindStringSubmatch(item)
+		if match == nil {
+			return nil
+		}
+		settings = append(settings, &Field{Name: match[1], Value: match[2]})
+	}
+	return settings
+}
+
+func (x *Select) beautifySettings() string {
+	sql := strings.Builder{}
+	for i, setting := range x.Settings {
+		if i == 0 {
+			sql.WriteString(consts.NextLine)
+			sql.WriteString(x.align("settings"))
+			sql.WriteString(consts.Blank)
+		} else {
+			sql.WriteString(", ")
+		}
+		sql.WriteString(setting.Name)
+		sql.WriteString(" = ")
+		sql.WriteString(setting.Value)
+	}
+	if x.Format != consts.Empty {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(x.align("format"))
+		sql.WriteString(consts.Blank)
+		sql.WriteString(x.Format)
+	}
+	return sql.String()
+}
+
+// 提取clickhouse的final以及sample并按紧邻的表名或别名归类，返回去除之后的sql
+func extractFinalSamples(sql string, finals map[string]bool, samples map[string]string) string {
+	for _, pattern := range []*regexp.Regexp{finalPattern, samplePattern} { // final位于sample之前（from t final sample 0.1）
+		matches := pattern.FindAllStringSubmatchIndex(sql, -1)
+		for i := len(matches) - 1; i >= 0; i-- { // 倒序处理，避免截取之后下标失效
+			match := matches[i]
+			prefix := sql[:match[0]]
+			if strings.Count(prefix, consts.LeftBracket) != strings.Count(prefix, consts.RightBracket) {
+				continue
+			}
+			words := strings.Fields(prefix)
+			if !followsTable(words) {
+				continue // 同名字段（select final from t）
+			}
+			key := words[len(words)-1]
+			if pattern == finalPattern {
+				finals[key] = true
+			} else if samples[key] = "sample " + sql[match[2]:match[3]]; match[6] >= 0 {
+				samples[key] += " offset " + sql[match[6]:match[7]]
+			}
+			sql = prefix + sql[match[len(match)-2]:] // 保留末尾的空白
+		}
+	}
+	return sql
+}
+
+// 末尾单词是否为表名或表别名（from t、join t、from t a、from t as a）
+func followsTable(words []string) bool {
+	var n = len(words)
+	var isFrom = func(word string) bool {
+		return strings.EqualFold(word, consts.FROM) || strings.EqualFold(word, consts.JOIN)
+	}
+	switch {
+	case n >= 2 && isFrom(words[n-2]):
+		return true
+	case n >= 4 && strings.EqualFold(words[n-2], consts.AS):
+		return isFrom(words[n-4])
+	case n >= 3 && isFrom(words[n-3]):
+		switch strings.ToLower(words[n-1]) {
+		case consts.WHERE, consts.ON, consts.AND, consts.OR, "prewhere", consts.GROUP, consts.ORDER, consts.LIMIT:
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// 提取数组展开（array join、left array join），仅处理当前查询层级
+func (x *Select) parseArrayJoins() *Select {
+	if !x.supports(ArrayJoinClause) {
+		return x
+	}
+	sql := x.tempSql
+	for {
+		var match []int
+		for _, m := range arrayJoinPattern.FindAllStringSubmatchIndex(sql, -1) {
+			if prefix := sql[:m[0]]; strings.Count(prefix, consts.LeftBracket) == strings.Count(prefix, consts.RightBracket) {
+				match = m
+				break
+			}
+		}
+		if match == nil {
+			break
+		}
+		rest, end := sql[match[1]:], len(sql)-match[1]
+		if _, i := utils.ContainsKeywords(strings.ToLower(rest), "array", consts.LEFT, consts.RIGHT, consts.INNER, consts.CROSS, consts.JOIN,
+			consts.GLOBAL, consts.ANY, consts.ALL, "prewhere", consts.WHERE, consts.GROUPBY, consts.HAVING); i >= 0 {
+			end = i - 1
+		}
+		var arrayJoin = &ArrayJoin{Left: match[2] >= 0}
+		list, last := utils.SplitExcludeInBracket(rest[:end], consts.Comma)
+		for _, field := range append(list, last) {
+			arrayJoin.Fields = append(arrayJoin.Fields, strings.TrimSpace(field))
+		}
+		x.ArrayJoins = append(x.ArrayJoins, arrayJoin)
+		sql = sql[:match[0]] + rest[end:]
+	}
+	x.tempSql = sql
+	return x
+}
+
+func (x *Select) beautifyArrayJoins() string {
+	sql := strings.Builder{}
+	for _, arrayJoin := range x.ArrayJoins {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(x.align(arrayJoin.beautify()))
+	}
+	return sql.String()
+}
+
+// 提取prewhere条件，仅处理当前查询层级
+func (x *Select) parsePrewhere() *Select {
+	sql := x.tempSql
+	if !x.supports(PrewhereClause) {
+		return x
+	}
+	if index := utils.IndexExcludeBrackets(strings.ToLower(sql), "prewhere", true); index > 0 {
+		var prewhereSql, rest = sql[index+9:], consts.Empty
+		if _, end := utils.ContainsKeywords(prewhereSql, consts.WHERE, consts.GROUPBY, consts.HAVING); end >= 0 {
+			prewhereSql, rest = prewhereSql[:end], prewhereSql[end:]
+		}
+		x.Prewhere = newConditions(prewhereSql, x.dialect, x.indent)
+		x.tempSql = sql[:index] + rest
+	}
+	return x
+}
+
+func (x *Select) beautifyPrewhere() string {
+	if conditions := x.Prewhere; len(conditions) > 0 {
+		sql := strings.Builder{}
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(x.align("prewhere"))
+		sql.WriteString(consts.Blank)
+		for i, condition := range conditions {
+			if i > 0 {
+				sql.WriteString(consts.NextLine)
+			}
+			sql.WriteString(condition.beautify(x.indent))
+		}
+		return sql.String()
+	}
+	return ""
+}
+
+// 提取分组修饰（with totals、with rollup、with cube），仅处理当前查询层级
+func (x *Select) parseGroupByWith() *Select {
+	sql := x.tempSql
+	for _, match := range groupByWithPattern.FindAllStringSubmatchIndex(sql, -1) {
+		if prefix := sql[:match[0]]; strings.Count(prefix, consts.LeftBracket) == strings.Count(prefix, consts.RightBracket) {
+			x.GroupByWith = "with " + strings.ToLower(sql[match[2]:match[3]])
+			x.tempSql = prefix + sql[match[3]:]
+			break
+		}
+	}
+	return x
+}
+
+// 提取分组限数（limit 5 by domain），需要先于limit解析
+func (x *Select) parseLimitBy() *Select {
+	if !x.supports(LimitByClause) {
+		return x
+	}
+	sql := x.tempSql
+	lower := strings.ToLower(sql)
+	for offset := 0; ; {
+		index := utils.IndexExcludeBrackets(lower[offset:], " limit ", false)
+		if index < 0 {
+			break
+		}
+		start := offset + index
+		end := len(sql)
+		if next := utils.IndexExcludeBrackets(lower[start+7:], " limit ", false); next >= 0 {
+			end = start + 7 + next
+		}
+		if match := limitByPattern.FindStringSubmatch(sql[start+7 : end]); match != nil {
+			var limitBy = &LimitBy{Limit: match[1]}
+			list, last := utils.SplitExcludeInBracket(match[2], consts.Comma)
+			for _, column := range append(list, last) {
+				limitBy.Columns = append(limitBy.Columns, strings.TrimSpace(column))
+			}
+			x.LimitBy = limitBy
+			x.tempSql = sql[:start] + sql[end:]
+			break
+		}
+		offset = start + 7
+	}
+	return x
+}
+
+func (x *Select) beautifyLimitBy() string {
+	if x.LimitBy == nil {
+		return ""
+	}
+	sql := strings.Builder{}
+	sql.WriteString(consts.NextLine)
+	sql.WriteString(x.align(consts.LIMIT))
+	sql.WriteString(consts.Blank)
+	sql.WriteString(x.LimitBy.Limit)
+	sql.WriteString(" by ")
+	sql.WriteString(strings.Join(x.LimitBy.Columns, ", "))
+	return sql.String()
+}
+
+// 解析clickhouse分布式条件（global in、global not in）
+func (c *Condition) parseGlobal() {
+	if c.Operator == consts.IN || c.Operator == consts.NOTIN {
+		if suffix := consts.Blank + consts.GLOBAL; strings.HasSuffix(strings.ToLower(c.Name), suffix) {
+			c.Name, c.Global = strings.TrimSpace(c.Name[:len(c.Name)-len(suffix)]), true
+		}
+	}
+}
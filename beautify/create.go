@@ -0,0 +1,751 @@
+package beautify
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+	"github.com/go-xuan/sqlx/utils"
+)
+
+// ParseCreateSQL 解析create语句，根据create之后的对象类型进行分发
+func ParseCreateSQL(sql string, indent ...int) IParser {
+	return parseCreate(sql, nil, indent...)
+}
+
+// 按方言解析create语句，未指定方言时为nil
+func parseCreate(sql string, dialect Dialect, indent ...int) IParser {
+	for _, word := range strings.Fields(sql)[1:] {
+		switch strings.ToLower(word) {
+		case consts.TABLE:
+			return parseCreateTable(compact(sql), dialect, indent...)
+		case consts.INDEX, consts.UNIQUE, consts.FULLTEXT, consts.SPATIAL:
+			return parseCreateIndex(compact(sql), dialect, indent...)
+		case consts.VIEW:
+			return parseCreateView(compact(sql), dialect, indent...)
+		case consts.FUNCTION, consts.PROCEDURE:
+			return parseCreateRoutine(sql, dialect, indent...)
+		case consts.TRIGGER:
+			return parseCreateTrigger(sql, dialect, indent...)
+		case consts.TEMPORARY, "temp", "external", "global", "local", "unlogged", "or", "replace", consts.MATERIALIZED,
+			"sql", "security", "definer", "invoker", consts.CONSTRAINT:
+			continue // 修饰词，继续判断
+		default:
+			if strings.HasPrefix(strings.ToLower(word), "algorithm") || strings.HasPrefix(strings.ToLower(word), "definer") {
+				continue // mysql视图选项
+			}
+			panic("当前输入sql无法解析 " + sql)
+		}
+	}
+	panic("当前输入sql无法解析 " + sql)
+}
+
+// ParseCreateTableSQL 解析建表SQL
+func ParseCreateTableSQL(sql string, indent ...int) *CreateTable {
+	return parseCreateTable(sql, nil, indent...)
+}
+
+// 按方言解析建表SQL，未指定方言时为nil
+func parseCreateTable(sql string, dialect Dialect, indent ...int) *CreateTable {
+	// sql初始化
+	var parser = &CreateTable{
+		Base: NewBase(sql, indent...),
+	}
+	parser.dialect = dialect
+
+	// sql解析
+	parser.parsePrepare()     // 解析准备
+	parser.parseTable()       // 解析表名
+	parser.parseDefinitions() // 解析字段、约束和索引
+	parser.parseOptions()     // 解析表选项
+	parser.parseFinish()      // 解析完成
+
+	return parser
+}
+
+type CreateTable struct {
+	Base
+	Table       *Table        // 建表对象
+	Temporary   bool          // 是否临时表
+	External    bool          // 是否外部表（hive）
+	IfNotExists bool          // 是否if not exists
+	Columns     []*Field      // 字段定义
+	PrimaryKey  *Constraint   // 主键约束
+	Uniques     []*Constraint // 唯一约束
+	ForeignKeys []*Constraint // 外键约束
+	Checks      []*Constraint // 检查约束
+	Indexes     []*Index      // 索引
+	Options     *TableOption  // 表选项
+	Query       *Select       // create table ... as select
+	Like        *Table        // create table ... like，复制表结构的来源表
+}
+
+// Constraint 表级约束解析
+type Constraint struct {
+	Name       string   // 约束名
+	Type       string   // 约束类型（primary key、unique、foreign key、check）
+	Columns    []string // 约束字段
+	RefTable   string   // 外键关联表
+	RefColumns []string // 外键关联字段
+	OnDelete   string   // 外键删除动作
+	OnUpdate   string   // 外键更新动作
+	Check      string   // 检查表达式
+	Extra      string   // 其他约束选项原文
+	keyName    bool     // 约束名写在约束类型之后，例如mysql的unique key uk_name (name)
+}
+
+// Index 索引解析
+type Index struct {
+	Name    string   // 索引名
+	Type    string   // 索引类型（key、index、fulltext、spatial）
+	Columns []string // 索引字段
+	Using   string   // 索引方法（btree、hash）
+	Extra   string   // 其他索引选项原文
+}
+
+// TableOption 表选项解析
+type TableOption struct {
+	Engine        string   // 存储引擎
+	Charset       string   // 字符集
+	Collate       string   // 排序规则
+	AutoIncrement string   // 自增起始值
+	Comment       string   // 表注释
+	PartitionBy   string   // 分区定义，partition by之后的部分
+	Others        []string // 其他选项原文（without rowid、tablespace等）
+
+	PartitionedBy []*Field // hive：分区字段（partitioned by (dt string)）
+	ClusteredBy   string   // hive：分桶定义，clustered by之后的部分
+	RowFormat     string   // hive：行格式，row format之后的部分
+	StoredAs      string   // hive：存储格式（orc、parquet）
+	Location      string   // hive：存储路径
+	Properties    string   // hive：表属性，tblproperties之后的部分
+}
+
+// Beautify SQL美化输出
+func (x *CreateTable) Beautify() string {
+	var sql = strings.Builder{}
+	sql.WriteString(x.beautifyCreate())
+	sql.WriteString(x.beautifyDefinitions())
+	sql.WriteString(x.beautifyOptions())
+	return x.finish(sql.String())
+}
+
+// 提取建表表名
+func (x *CreateTable) parseTable() *CreateTable {
+	sql := x.tempSql
+	// 表名之后的部分：字段定义括号或者as子查询
+	var header, rest = sql, consts.Empty
+	if index := utils.IndexOfString(sql, consts.LeftBracket); index >= 0 {
+		header, rest = sql[:index], sql[index:]
+	}
+	words := utils.SplitFieldsExcludeInBracket(header) // 引号内的表名可能包含空格（`order items`）
+	var i int
+	for ; i < len(words); i++ {
+		switch word := strings.ToLower(words[i]); word {
+		case consts.CREATE:
+		case consts.TEMPORARY, "temp":
+			x.Temporary = true
+		case "external":
+			x.External = true
+		case consts.TABLE:
+			i++
+			if i+2 < len(words) && strings.EqualFold(strings.Join(words[i:i+3], consts.Blank), consts.IFNOTEXISTS) {
+				x.IfNotExists = true
+				i += 3
+			}
+			if i < len(words) {
+				x.Table = NewTable(words[i])
+			}
+			// 表名之后如果是as，表示create table ... as select；如果是like，表示复制来源表结构
+			if i+1 < len(words) && strings.EqualFold(words[i+1], consts.AS) {
+				rest = strings.Join(words[i+2:], consts.Blank) + rest
+			} else if i+2 < len(words) && strings.EqualFold(words[i+1], consts.LIKE) {
+				x.Like = NewTable(words[i+2])
+				rest = strings.Join(words[i+3:], consts.Blank) + rest
+			}
+			i = len(words)
+		}
+	}
+	if x.Table == nil {
+		panic("当前输入sql无法解析 " + x.originSql)
+	}
+	x.tempSql = strings.TrimSpace(rest)
+	return x
+}
+
+// 提取字段定义、约束和索引
+func (x *CreateTable) parseDefinitions() *CreateTable {
+	sql := x.tempSql
+	if sql == consts.Empty || x.Like != nil && sql[:1] != consts.LeftBracket {
+		return x
+	}
+	if index := utils.IndexOfKeywordFirst(strings.ToLower(sql), consts.SELECT); index >= 0 && sql[:1] != consts.LeftBracket {
+		x.Query = parseSelect(sql[index:], x.dialect)
+		x.tempSql = consts.Empty
+		return x
+	}
+	from, to := utils.BetweenOfString(sql, consts.LeftBracket, consts.RightBracket)
+	if from != 0 || to < 0 {
+		panic("当前输入sql无法解析 " + x.originSql)
+	}
+	list, last := utils.SplitExcludeInBracket(sql[from+1:to], consts.Comma)
+	list = append(list, last)
+	for _, definition := range list {
+		words := utils.SplitFieldsExcludeInBracket(definition)
+		if len(words) == 0 {
+			continue
+		}
+		switch strings.ToLower(words[0]) {
+		case consts.CONSTRAINT, consts.PRIMARY, consts.UNIQUE, consts.FOREIGN, consts.CHECK:
+			x.addConstraint(x.newConstraint(words))
+		case consts.KEY, consts.INDEX, consts.FULLTEXT, consts.SPATIAL:
+			x.Indexes = append(x.Indexes, x.newIndex(words))
+		default:
+			x.Columns = append(x.Columns, x.newColumn(words))
+		}
+	}
+	x.tempSql = sql[to+1:]
+	return x
+}
+
+func (x *CreateTable) addConstraint(constraint *Constraint) {
+	switch constraint.Type {
+	case consts.PRIMARYKEY:
+		x.PrimaryKey = constraint
+	case consts.UNIQUE:
+		x.Uniques = append(x.Uniques, constraint)
+	case consts.FOREIGNKEY:
+		x.ForeignKeys = append(x.ForeignKeys, constraint)
+	case consts.CHECK:
+		x.Checks = append(x.Checks, constraint)
+	}
+}
+
+// 解析字段定义
+func (b *Base) newColumn(words []string) *Field {
+	var field = &Field{Name: words[0], Nullable: true}
+	var i = 1
+	// 字段类型，sqlite允许不声明类型
+	if i < len(words) && !isColumnConstraint(words[i]) {
+		var types = []string{words[i]}
+		for i++; i < len(words); i++ {
+			word := strings.ToLower(words[i])
+			if word[:1] == consts.LeftBracket || word == "[]" ||
+				word == "unsigned" || word == "signed" || word == "zerofill" ||
+				strings.HasPrefix(word, "varying") || word == "precision" {
+				types = append(types, words[i])
+			} else if (word == "with" || word == "without") && i+2 < len(words) &&
+				strings.EqualFold(words[i+1], "time") && strings.EqualFold(words[i+2], "zone") {
+				types = append(types, words[i:i+3]...)
+				i += 2
+			} else {
+				break
+			}
+		}
+		field.Type = strings.Join(types, consts.Blank)
+		field.Type = strings.Replace(field.Type, consts.Blank+consts.LeftBracket, consts.LeftBracket, 1)
+		field.Precision, field.Scale = parsePrecision(field.Type)
+		field.Type = b.restore(field.Type)
+	}
+	// 列约束
+	var extras []string
+	for ; i < len(words); i++ {
+		switch word := strings.ToLower(words[i]); {
+		case word == consts.NOT && i+1 < len(words) && strings.EqualFold(words[i+1], consts.NULL):
+			field.Nullable = false
+			i++
+		case word == consts.NULL:
+			field.Nullable = true
+		case word == consts.DEFAULT && i+1 < len(words):
+			field.Default = b.restore(words[i+1])
+			i++
+		case word == consts.PRIMARY && i+1 < len(words) && strings.EqualFold(words[i+1], consts.KEY):
+			field.PrimaryKey, field.Nullable = true, false
+			i++
+		case word == consts.UNIQUE:
+			field.Unique = true
+			if i+1 < len(words) && strings.EqualFold(words[i+1], consts.KEY) {
+				i++
+			}
+		case word == consts.AUTOINCREMENT || word == consts.AUTOINCREMENTSQLITE:
+			field.AutoIncrement, field.incrementKeyword = true, word
+		case word == consts.COMMENT && i+1 < len(words):
+			field.Comment = b.restore(words[i+1])
+			i++
+		default:
+			extras = append(extras, words[i])
+		}
+	}
+	field.Extra = b.restore(strings.Join(extras, consts.Blank))
+	return field
+}
+
+// 解析表级约束
+func (b *Base) newConstraint(words []string) *Constraint {
+	var constraint = &Constraint{}
+	var i int
+	if strings.EqualFold(words[0], consts.CONSTRAINT) && len(words) > 2 {
+		constraint.Name = words[1]
+		i = 2
+	}
+	switch strings.ToLower(words[i]) {
+	case consts.PRIMARY:
+		constraint.Type = consts.PRIMARYKEY
+		i += 2
+	case consts.FOREIGN:
+		constraint.Type = consts.FOREIGNKEY
+		i += 2
+	case consts.UNIQUE:
+		constraint.Type = consts.UNIQUE
+		if i++; i < len(words) && (strings.EqualFold(words[i], consts.KEY) || strings.EqualFold(words[i], consts.INDEX)) {
+			i++
+		}
+	case consts.CHECK:
+		constraint.Type = consts.CHECK
+		i++
+		if i < len(words) {
+			constraint.Check = b.restore(utils.TrimBrackets(words[i]))
+			i++
+		}
+	}
+	var extras []string
+	for ; i < len(words); i++ {
+		word := words[i]
+		if name, columns := splitNameColumns(word); columns != consts.Empty {
+			if constraint.RefTable != consts.Empty {
+				if name != consts.Empty {
+					constraint.RefTable = name
+				}
+				constraint.RefColumns = splitColumns(columns)
+			} else {
+				if name != consts.Empty && constraint.Name == consts.Empty {
+					constraint.Name, constraint.keyName = name, true
+				}
+				constraint.Columns = splitColumns(columns)
+			}
+			continue
+		}
+		switch strings.ToLower(word) {
+		case consts.REFERENCES:
+			if i+1 < len(words) {
+				var columns string
+				constraint.RefTable, columns = splitNameColumns(words[i+1])
+				if columns != consts.Empty {
+					constraint.RefColumns = splitColumns(columns)
+				}
+				i++
+			}
+		case consts.ON:
+			if i+2 < len(words) {
+				action, n := referenceAction(words[i+2:])
+				switch strings.ToLower(words[i+1]) {
+				case consts.DELETE:
+					constraint.OnDelete = action
+				case consts.UPDATE:
+					constraint.OnUpdate = action
+				}
+				i += 1 + n
+			}
+		default:
+			if constraint.Name == consts.Empty && constraint.Columns == nil {
+				constraint.Name, constraint.keyName = word, true
+			} else {
+				extras = append(extras, word)
+			}
+		}
+	}
+	constraint.Extra = b.restore(strings.Join(extras, consts.Blank))
+	return constraint
+}
+
+// 解析索引
+func (b *Base) newIndex(words []string) *Index {
+	var index = &Index{Type: strings.ToLower(words[0])}
+	var i = 1
+	if (index.Type == consts.FULLTEXT || index.Type == consts.SPATIAL) && i < len(words) &&
+		(strings.EqualFold(words[i], consts.KEY) || strings.EqualFold(words[i], consts.INDEX)) {
+		i++
+	}
+	var extras []string
+	for ; i < len(words); i++ {
+		if name, columns := splitNameColumns(words[i]); columns != consts.Empty {
+			if name != consts.Empty {
+				index.Name = name
+			}
+			index.Columns = splitColumns(columns)
+		} else if strings.EqualFold(words[i], consts.USING) && i+1 < len(words) {
+			index.Using = words[i+1]
+			i++
+		} else if index.Name == consts.Empty && index.Columns == nil {
+			index.Name = words[i]
+		} else {
+			extras = append(extras, words[i])
+		}
+	}
+	index.Extra = b.restore(strings.Join(extras, consts.Blank))
+	return index
+}
+
+// 提取表选项
+func (x *CreateTable) parseOptions() *CreateTable {
+	sql := strings.TrimSpace(x.tempSql)
+	sql = strings.TrimSpace(strings.TrimSuffix(sql, consts.Semicolon))
+	if sql == consts.Empty {
+		return x
+	}
+	var option = &TableOption{}
+	sql = x.parseHiveClauses(sql, option)
+	if index := utils.IndexOfString(strings.ToLower(sql), consts.PARTITIONBY); index >= 0 {
+		option.PartitionBy = x.restore(strings.TrimSpace(sql[index+12:]))
+		sql = strings.TrimSpace(sql[:index])
+	}
+	// 统一等号两侧空格，engine = innodb → engine=innodb
+	sql = regexp.MustCompile(`\s*=\s*`).ReplaceAllString(sql, consts.EQ)
+	words := utils.SplitFieldsExcludeInBracket(sql)
+	for i := 0; i < len(words); i++ {
+		word := strings.TrimSuffix(words[i], consts.Comma)
+		key, value, ok := strings.Cut(word, consts.EQ)
+		key = strings.ToLower(key)
+		if key == consts.CHARACTER && i+1 < len(words) { // character set
+			key, value, ok = strings.Cut(words[i+1], consts.EQ)
+			key = consts.CHARSET
+			i++
+		}
+		switch key {
+		case consts.DEFAULT, consts.Empty:
+			continue
+		case consts.ENGINE, consts.CHARSET, consts.COLLATE, consts.AUTOINCREMENT, consts.COMMENT:
+			if !ok && i+1 < len(words) {
+				value = words[i+1]
+				i++
+			}
+			value = x.restore(value)
+			switch key {
+			case consts.ENGINE:
+				option.Engine = value
+			case consts.CHARSET:
+				option.Charset = value
+			case consts.COLLATE:
+				option.Collate = value
+			case consts.AUTOINCREMENT:
+				option.AutoIncrement = value
+			case consts.COMMENT:
+				option.Comment = value
+			}
+		default:
+			option.Others = append(option.Others, x.restore(word))
+		}
+	}
+	x.Options = option
+	x.tempSql = consts.Empty
+	return x
+}
+
+// 构建建表语句头
+func (x *CreateTable) beautifyCreate() string {
+	var sql = strings.Builder{}
+	sql.WriteString(consts.CREATE)
+	sql.WriteString(consts.Blank)
+	if x.Temporary {
+		sql.WriteString(consts.TEMPORARY)
+		sql.WriteString(consts.Blank)
+	}
+	if x.External {
+		sql.WriteString("external ")
+	}
+	sql.WriteString(consts.TABLE)
+	sql.WriteString(consts.Blank)
+	if x.IfNotExists {
+		sql.WriteString(consts.IFNOTEXISTS)
+		sql.WriteString(consts.Blank)
+	}
+	sql.WriteString(x.Table.beautify())
+	if x.Like != nil {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.LIKE)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(x.Like.beautify())
+	}
+	if x.Query != nil {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.AS)
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(x.Query.Beautify())
+	}
+	return sql.String()
+}
+
+// 构建字段定义、约束和索引，字段名、类型、约束分别按列对齐
+func (x *CreateTable) beautifyDefinitions() string {
+	if x.Query != nil || x.Like != nil && len(x.Columns) == 0 {
+		return consts.Empty
+	}
+	var lines []string
+	var nameAlign, typeAlign int
+	for _, column := range x.Columns {
+		if l := utils.DisplayWidth(column.Name); nameAlign < l {
+			nameAlign = l
+		}
+		if l := utils.DisplayWidth(column.Type); typeAlign < l {
+			typeAlign = l
+		}
+	}
+	for _, column := range x.Columns {
+		var line = strings.Builder{}
+		line.WriteString(column.Name)
+		constraints := column.constraints()
+		if column.Type != consts.Empty || len(constraints) > 0 {
+			line.WriteString(strings.Repeat(consts.Blank, nameAlign-utils.DisplayWidth(column.Name)+1))
+			line.WriteString(column.Type)
+		}
+		if len(constraints) > 0 {
+			line.WriteString(strings.Repeat(consts.Blank, typeAlign-utils.DisplayWidth(column.Type)+1))
+			line.WriteString(strings.Join(constraints, consts.Blank))
+		}
+		lines = append(lines, line.String())
+	}
+	if x.PrimaryKey != nil {
+		lines = append(lines, x.PrimaryKey.beautify())
+	}
+	for _, constraint := range x.Uniques {
+		lines = append(lines, constraint.beautify())
+	}
+	for _, index := range x.Indexes {
+		lines = append(lines, index.beautify())
+	}
+	for _, constraint := range x.ForeignKeys {
+		lines = append(lines, constraint.beautify())
+	}
+	for _, constraint := range x.Checks {
+		lines = append(lines, constraint.beautify())
+	}
+	var sql = strings.Builder{}
+	sql.WriteString(consts.Blank)
+	sql.WriteString(consts.LeftBracket)
+	for i, line := range lines {
+		if i > 0 {
+			sql.WriteString(consts.Comma)
+		}
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(x.align())
+		sql.WriteString(line)
+	}
+	sql.WriteString(consts.NextLine)
+	sql.WriteString(consts.RightBracket)
+	return sql.String()
+}
+
+// 构建表选项
+func (x *CreateTable) beautifyOptions() string {
+	var sql = strings.Builder{}
+	if option := x.Options; option != nil {
+		var writeOption = func(key, value string) {
+			if value != consts.Empty {
+				sql.WriteString(consts.Blank)
+				sql.WriteString(key)
+				sql.WriteString(consts.EQ)
+				sql.WriteString(value)
+			}
+		}
+		writeOption(consts.ENGINE, option.Engine)
+		writeOption(consts.AUTOINCREMENT, option.AutoIncrement)
+		writeOption(consts.DEFAULT+consts.Blank+consts.CHARSET, option.Charset)
+		writeOption(consts.COLLATE, option.Collate)
+		if !option.isHive() { // hive表注释不使用等号，和hive建表子句一并输出
+			writeOption(consts.COMMENT, option.Comment)
+		}
+		for _, other := range option.Others {
+			sql.WriteString(consts.Blank)
+			sql.WriteString(other)
+		}
+		if option.PartitionBy != consts.Empty {
+			sql.WriteString(consts.NextLine)
+			sql.WriteString(consts.PARTITIONBY)
+			sql.WriteString(consts.Blank)
+			sql.WriteString(option.PartitionBy)
+		}
+		sql.WriteString(option.beautifyHiveClauses())
+	}
+	return sql.String()
+}
+
+// 字段定义，字段名、类型、约束以单个空格分隔
+func (f *Field) definition() string {
+	var words = []string{f.Name}
+	if f.Type != consts.Empty {
+		words = append(words, f.Type)
+	}
+	return strings.Join(append(words, f.constraints()...), consts.Blank)
+}
+
+// 字段约束，按固定顺序输出
+func (f *Field) constraints() []string {
+	var constraints []string
+	if !f.Nullable && !f.PrimaryKey {
+		constraints = append(constraints, consts.NOTNULL)
+	}
+	if f.Default != consts.Empty {
+		constraints = append(constraints, consts.DEFAULT+consts.Blank+f.Default)
+	}
+	if f.PrimaryKey {
+		constraints = append(constraints, consts.PRIMARYKEY)
+	}
+	if f.AutoIncrement {
+		if f.incrementKeyword != consts.Empty {
+			constraints = append(constraints, f.incrementKeyword)
+		} else {
+			constraints = append(constraints, consts.AUTOINCREMENT)
+		}
+	}
+	if f.Unique {
+		constraints = append(constraints, consts.UNIQUE)
+	}
+	if f.Extra != consts.Empty {
+		constraints = append(constraints, f.Extra)
+	}
+	if f.Comment != consts.Empty {
+		constraints = append(constraints, consts.COMMENT+consts.Blank+f.Comment)
+	}
+	return constraints
+}
+
+func (c *Constraint) beautify() string {
+	var sql = strings.Builder{}
+	if c.Name != consts.Empty && !c.keyName {
+		sql.WriteString(consts.CONSTRAINT)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(c.Name)
+		sql.WriteString(consts.Blank)
+	}
+	sql.WriteString(c.Type)
+	if c.Name != consts.Empty && c.keyName {
+		if c.Type == consts.UNIQUE {
+			sql.WriteString(consts.Blank)
+			sql.WriteString(consts.KEY)
+		}
+		sql.WriteString(consts.Blank)
+		sql.WriteString(c.Name)
+	}
+	if c.Type == consts.CHECK {
+		sql.WriteString(" (")
+		sql.WriteString(c.Check)
+		sql.WriteString(consts.RightBracket)
+	} else {
+		sql.WriteString(" (")
+		sql.WriteString(strings.Join(c.Columns, ", "))
+		sql.WriteString(consts.RightBracket)
+	}
+	if c.RefTable != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.REFERENCES)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(c.RefTable)
+		if len(c.RefColumns) > 0 {
+			sql.WriteString(" (")
+			sql.WriteString(strings.Join(c.RefColumns, ", "))
+			sql.WriteString(consts.RightBracket)
+		}
+	}
+	if c.OnDelete != consts.Empty {
+		sql.WriteString(" on delete ")
+		sql.WriteString(c.OnDelete)
+	}
+	if c.OnUpdate != consts.Empty {
+		sql.WriteString(" on update ")
+		sql.WriteString(c.OnUpdate)
+	}
+	if c.Extra != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(c.Extra)
+	}
+	return sql.String()
+}
+
+func (i *Index) beautify() string {
+	var sql = strings.Builder{}
+	sql.WriteString(i.Type)
+	if i.Type == consts.FULLTEXT || i.Type == consts.SPATIAL {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.KEY)
+	}
+	if i.Name != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(i.Name)
+	}
+	sql.WriteString(" (")
+	sql.WriteString(strings.Join(i.Columns, ", "))
+	sql.WriteString(consts.RightBracket)
+	if i.Using != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.USING)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(i.Using)
+	}
+	if i.Extra != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(i.Extra)
+	}
+	return sql.String()
+}
+
+// 是否列约束关键字（sqlite字段可以不声明类型，直接跟约束）
+func isColumnConstraint(word string) bool {
+	switch strings.ToLower(word) {
+	case consts.NOT, consts.NULL, consts.DEFAULT, consts.PRIMARY, consts.UNIQUE, consts.CHECK,
+		consts.REFERENCES, consts.CONSTRAINT, consts.COMMENT, consts.COLLATE, consts.AUTOINCREMENT, consts.AUTOINCREMENTSQLITE, "generated":
+		return true
+	default:
+		return false
+	}
+}
+
+// 解析字段类型中的长度和小数位，例如decimal(10,2)
+func parsePrecision(dataType string) (precision, scale int) {
+	if from, to := utils.BetweenOfString(dataType, consts.LeftBracket, consts.RightBracket); from >= 0 && from < to {
+		args := strings.Split(dataType[from+1:to], consts.Comma)
+		if len(args) > 2 {
+			return 0, 0
+		}
+		var err error
+		if precision, err = strconv.Atoi(strings.TrimSpace(args[0])); err != nil {
+			return 0, 0
+		}
+		if len(args) == 2 {
+			if scale, err = strconv.Atoi(strings.TrimSpace(args[1])); err != nil {
+				return 0, 0
+			}
+		}
+	}
+	return
+}
+
+// 拆分名称和字段列表，例如 idx_name(a, b) → idx_name, (a, b)
+func splitNameColumns(word string) (string, string) {
+	if index := utils.IndexOfString(word, consts.LeftBracket); index >= 0 && word[len(word)-1:] == consts.RightBracket {
+		return word[:index], word[index:]
+	}
+	return word, consts.Empty
+}
+
+// 拆分括号内的字段列表
+func splitColumns(sql string) []string {
+	var columns []string
+	for _, column := range utils.SplitValuesSql(sql) {
+		if column != consts.Empty {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// 外键动作，返回动作以及占用的单词数
+func referenceAction(words []string) (string, int) {
+	if len(words) > 1 {
+		switch first := strings.ToLower(words[0]); first {
+		case consts.SET, "no":
+			return first + consts.Blank + strings.ToLower(words[1]), 2
+		}
+	}
+	return strings.ToLower(words[0]), 1
+}
@@ -9,28 +9,66 @@ import (
 
 // ParseDeleteSQL 解析删除SQL
 func ParseDeleteSQL(sql string, indent ...int) *Delete {
+	return parseDelete(sql, nil, indent...)
+}
+
+// 按方言解析删除SQL，未指定方言时为nil
+func parseDelete(sql string, dialect Dialect, indent ...int) *Delete {
 	// sql初始化
 	var parser = &Delete{
 		Base: NewBase(sql, indent...),
 	}
+	parser.dialect = dialect
 
 	// sql解析
-	parser.parsePrepare() // 解析准备
-	parser.parseTable()   // 解析主表
-	parser.parseWhere()   // 解析查询条件
-	parser.parseFinish()  // 解析完成
+	parser.parsePrepare()   // 解析准备
+	parser.parseReturning() // 解析returning
+	parser.parseOrderBy()   // 解析order by以及limit
+	parser.parseTable()     // 解析主表
+	parser.parseWhere()     // 解析查询条件
+	parser.parseFinish()    // 解析完成
 
 	return parser
 }
 
 type Delete struct {
 	Base
-	Table *Table       // 删除表
-	Where []*Condition // 查询条件
+	Table     *Table       // 删除表
+	Where     []*Condition // 查询条件
+	OrderBy   []string     // 排序条件（mysql）
+	Limit     string       // 限数条件（mysql）
+	Output    *Output      // sql server：output deleted.*
+	Returning *Output      // oracle/postgresql：returning id into :id
 }
 
 func (x *Delete) Beautify() string {
-	return ""
+	var sql = strings.Builder{}
+	sql.WriteString(Align(x.format().Indent, consts.DELETE))
+	sql.WriteString(consts.Blank)
+	sql.WriteString(consts.FROM)
+	sql.WriteString(consts.Blank)
+	sql.WriteString(x.Table.beautify(x.format().aliasAs(false)))
+	if x.Output != nil {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(x.align(x.Output.beautify()))
+	}
+	if conditions := x.Where; len(conditions) > 0 {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(x.align(consts.WHERE))
+		sql.WriteString(consts.Blank)
+		for i, condition := range conditions {
+			if i > 0 {
+				sql.WriteString(consts.NextLine)
+			}
+			sql.WriteString(condition.beautify(x.indent))
+		}
+	}
+	sql.WriteString(x.beautifyOrderByLimit(x.OrderBy, x.Limit))
+	if x.Returning != nil {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(x.align(x.Returning.beautify()))
+	}
+	return x.finish(sql.String())
 }
 
 func (x *Delete) parseTable() *Delete {
@@ -48,22 +86,31 @@ func (x *Delete) parseTable() *Delete {
 		x.tempSql = sql[index:]
 		sql = sql[:index]
 	}
-	var name, alias string
-	if index := utils.IndexOfString(sql, consts.Blank, 1); index >= 0 {
+	var hints = make(map[string][]string)
+	if sql = x.extractTableHints(sql, hints); x.supports(OutputClause) {
+		sql, x.Output = cutOutput(sql)
+	}
+	var name, alias = strings.TrimSuffix(strings.TrimSpace(sql), consts.Semicolon), consts.Empty
+	if index := utils.IndexExcludeQuotes(sql, consts.Blank, 0); index >= 0 {
 		name = sql[:index]
 		alias = utils.ExtractAlias(sql[index+1:])
 	}
-	x.Table = &Table{
-		Name:  name,
-		Alias: alias,
-	}
+	x.Table = NewTable(name)
+	x.Table.Alias = alias
+	attachTableHints(x.Table, hints)
+	return x
+}
+
+// 提取order by以及limit
+func (x *Delete) parseOrderBy() *Delete {
+	x.tempSql, x.OrderBy, x.Limit = cutOrderByLimit(x.tempSql)
 	return x
 }
 
-// 提取查询条件
+// 提取查询条件，order by以及limit已提前截取，where之后均为条件
 func (x *Delete) parseWhere() *Delete {
-	if sql := x.tempSql; sql != "" {
-		x.Where, x.tempSql = ExtractWhere(sql)
+	if index := utils.IndexOfKeywordFirst(x.tempSql, consts.WHERE); index >= 0 {
+		x.Where, x.tempSql = newConditions(x.tempSql[index+5:], x.dialect, x.indent), consts.Empty
 	}
 	return x
 }
@@ -0,0 +1,102 @@
+package beautify
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-xuan/sqlx/utils"
+)
+
+// 方言特征，命中时为对应方言累加权重
+type dialectCue struct {
+	pattern *regexp.Regexp
+	weights map[Dialect]float64
+}
+
+func newCue(pattern string, weights map[Dialect]float64) *dialectCue {
+	return &dialectCue{pattern: regexp.MustCompile(`(?i)` + pattern), weights: weights}
+}
+
+// 方言特征，仅匹配引号之外的内容
+var dialectCues = []*dialectCue{
+	// 标识符引号
+	newCue("`", map[Dialect]float64{MySQL: 2, Hive: 2, ClickHouse: 1, SQLite: 0.5}),
+	newCue(`(^|[\s,(.=])\[[A-Za-z_#][^\]@,]*]`, map[Dialect]float64{SQLServer: 3, SQLite: 0.5}),
+	// 占位符以及变量
+	newCue(`\$\d+`, map[Dialect]float64{PostgreSQL: 3}),
+	newCue(`(^|[^:\w]):[A-Za-z_]\w*`, map[Dialect]float64{Oracle: 1}),
+	newCue(`(^|[^@\w])@[A-Za-z_]\w*`, map[Dialect]float64{SQLServer: 2}),
+	// 分页
+	newCue(`\slimit\s+\d+\s*,\s*\d+`, map[Dialect]float64{MySQL: 3, Hive: 1, ClickHouse: 1, SQLite: 1}),
+	newCue(`^select\s+(distinct\s+)?top\s*\(?\s*[\d@]`, map[Dialect]float64{SQLServer: 4}),
+	newCue(`\brownum\b`, map[Dialect]float64{Oracle: 4}),
+	newCue(`\soffset\s+\S+\s+rows?\s+fetch\s`, map[Dialect]float64{SQLServer: 1, Oracle: 1, PostgreSQL: 0.5}),
+	// 运算符以及语法
+	newCue(`::\s*[A-Za-z_]`, map[Dialect]float64{PostgreSQL: 3, ClickHouse: 0.5}),
+	newCue(`\silike\s`, map[Dialect]float64{PostgreSQL: 3, ClickHouse: 1}),
+	newCue(`distinct\s+on\s*\(`, map[Dialect]float64{PostgreSQL: 4}),
+	newCue(`\son\s+conflict\b`, map[Dialect]float64{PostgreSQL: 3, SQLite: 2}),
+	newCue(`\sreturning\s`, map[Dialect]float64{PostgreSQL: 2, Oracle: 1, SQLite: 1}),
+	newCue(`\son\s+duplicate\s+key\s+update\s`, map[Dialect]float64{MySQL: 4}),
+	newCue(`\b(straight_join|sql_calc_found_rows|sql_no_cache)\b|\s(force|use|ignore)\s+(index|key)\b`, map[Dialect]float64{MySQL: 4}),
+	newCue(`\b(ifnull|group_concat|date_format|str_to_date)\s*\(`, map[Dialect]float64{MySQL: 2}),
+	newCue(`\swith\s*\(\s*(nolock|readpast|updlock|holdlock|rowlock|tablock)`, map[Dialect]float64{SQLServer: 4}),
+	newCue(`\s(cross|outer)\s+apply\s`, map[Dialect]float64{SQLServer: 3}),
+	newCue(`\b(getdate|isnull|datediff|len)\s*\(|\soutput\s+(inserted|deleted)\.`, map[Dialect]float64{SQLServer: 2}),
+	newCue(`\sconnect\s+by\s|\sstart\s+with\s|\(\s*\+\s*\)|\sfrom\s+dual\b|\bsysdate\b|\sminus\s`, map[Dialect]float64{Oracle: 4}),
+	newCue(`\b(nvl|nvl2|decode|to_date)\s*\(`, map[Dialect]float64{Oracle: 2, Hive: 0.5}),
+	newCue(`\blateral\s+view\s|\s(distribute|sort|cluster)\s+by\s|\sinsert\s+overwrite\s|\sstored\s+as\s|\spartitioned\s+by\s|\stablesample\s*\(`,
+		map[Dialect]float64{Hive: 4}),
+	newCue(`\sprewhere\s|\ssettings\s+\w+\s*=\s*[^\s=]|\sfinal(\s|$)|\sarray\s+join\s|\sglobal\s+(not\s+)?in\s|\sformat\s+`+formatName+`\s*;?$|\swith\s+totals\b`,
+		map[Dialect]float64{ClickHouse: 4}),
+	newCue(`\s(any|asof)\s+(left|inner|right)?\s*join\s|\slimit\s+\d+\s+by\s`, map[Dialect]float64{ClickHouse: 4}),
+	newCue(`^\s*pragma\s|\sautoincrement\b|\sglob\s`, map[Dialect]float64{SQLite: 4}),
+}
+
+// DialectScore 方言候选及其置信度
+type DialectScore struct {
+	Dialect    Dialect // 候选方言
+	Confidence float64 // 置信度（0~1），全部候选之和为1
+}
+
+// DetectDialect 根据语法特征（反引号、$1、::、top、rownum、limit x, y、[标识符]、ilike、settings等）推断sql所属方言，
+// 返回置信度最高的方言以及置信度，没有任何特征时返回nil
+func DetectDialect(sql string) (Dialect, float64) {
+	if scores := DetectDialects(sql); len(scores) > 0 {
+		return scores[0].Dialect, scores[0].Confidence
+	}
+	return nil, 0
+}
+
+// DetectDialects 根据语法特征推断sql所属方言，返回按置信度从高到低排列的候选方言
+func DetectDialects(sql string) []*DialectScore {
+	sql, values := utils.ExtractValuesInSql(strings.TrimSpace(sql))
+	var weights = make(map[Dialect]float64)
+	for i := 1; i < len(values); i += 2 {
+		if strings.HasPrefix(values[i], "$") { // $$...$$
+			weights[PostgreSQL] += 3
+		}
+	}
+	for _, cue := range dialectCues {
+		if cue.pattern.MatchString(sql) {
+			for dialect, weight := range cue.weights {
+				weights[dialect] += weight
+			}
+		}
+	}
+	var total float64
+	for _, weight := range weights {
+		total += weight
+	}
+	var scores []*DialectScore
+	for _, dialect := range Dialects() { // 按内置方言顺序遍历，保证同分时结果稳定
+		if weight := weights[dialect]; weight > 0 {
+			scores = append(scores, &DialectScore{Dialect: dialect, Confidence: weight / total})
+		}
+	}
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Confidence > scores[j].Confidence
+	})
+	return scores
+}
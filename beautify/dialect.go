@@ -0,0 +1,383 @@
+package beautify
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Pagination 分页语法
+type Pagination string
+
+const (
+	LimitOffsetPagination Pagination = "limit n offset m"                     // postgresql、sqlite、clickhouse
+	LimitCommaPagination  Pagination = "limit m, n"                           // mysql、hive
+	OffsetFetchPagination Pagination = "offset m rows fetch next n rows only" // oracle 12c、sql server 2012
+	TopPagination         Pagination = "top n"                                // sql server（无偏移）
+	RownumPagination      Pagination = "where rownum <= n"                    // oracle 11g及以下
+)
+
+// Clause 方言特有子句
+type Clause string
+
+const (
+	LimitClause       Clause = "limit"         // limit n
+	OffsetFetchClause Clause = "offset fetch"  // offset m rows fetch next n rows only
+	TopClause         Clause = "top"           // top n
+	RownumClause      Clause = "rownum"        // where rownum <= n
+	ReturningClause   Clause = "returning"     // returning id
+	OutputClause      Clause = "output"        // output inserted.id
+	DistinctOnClause  Clause = "distinct on"   // distinct on (a)
+	ConnectByClause   Clause = "connect by"    // start with ... connect by prior a = b
+	SetMinusClause    Clause = "minus"         // minus
+	ApplyClause       Clause = "apply"         // cross apply、outer apply
+	TableHintClause   Clause = "table hint"    // force index (idx)、with (nolock)
+	LateralViewClause Clause = "lateral view"  // lateral view explode(x) t as c
+	DistributeClause  Clause = "distribute by" // distribute by a sort by b
+	ArrayJoinClause   Clause = "array join"    // array join arr
+	PrewhereClause    Clause = "prewhere"      // prewhere a = 1
+	FinalClause       Clause = "final"         // from t final
+	SampleClause      Clause = "sample"        // sample 0.1、tablesample (10 percent)
+	LimitByClause     Clause = "limit by"      // limit 5 by domain
+	SettingsClause    Clause = "settings"      // settings max_threads = 8
+	FormatClause      Clause = "format"        // format JSONEachRow
+	OnDuplicateClause Clause = "on duplicate"  // insert ... on duplicate key update、insert ignore
+	OnConflictClause  Clause = "on conflict"   // insert ... on conflict (id) do update set
+	BooleanClause     Clause = "boolean"       // true、false布尔字面量
+)
+
+// Dialect 数据库方言，描述关键字、标识符引号、字符串转义、占位符、分页语法以及支持的子句
+type Dialect interface {
+	Name() string                       // 方言名称
+	Keywords() []string                 // 保留关键字（小写）
+	IsKeyword(word string) bool         // 是否保留关键字（忽略大小写）
+	Functions() []string                // 内置函数（小写，区分大小写的函数为其原始名称，例如clickhouse的toDate）
+	IsFunction(word string) bool        // 是否内置函数（忽略大小写）
+	Quotes() []string                   // 标识符引号（`、"、[），首个为默认引号
+	QuoteIdentifier(name string) string // 使用默认引号引用标识符
+	EscapeString(value string) string   // 转义字符串并以单引号引用
+	UnescapeString(sql string) string   // 去除字符串字面量的单引号并还原转义字符
+	PlaceholderStyle() PlaceholderStyle // 绑定占位符风格
+	Placeholder(position int) string    // 第position（从1开始）个绑定占位符
+	Pagination() Pagination             // 分页语法
+	Supports(clause Clause) bool        // 是否支持子句
+	Operators() []string                // 方言特有的条件运算符（小写），按顺序匹配
+}
+
+// 各方言通用的保留关键字
+var commonKeywords = []string{
+	"select", "insert", "update", "delete", "merge", "into", "values", "set", "from", "where", "join", "inner", "left", "right", "outer",
+	"full", "cross", "on", "using", "group", "by", "order", "having", "union", "all", "intersect", "except", "distinct", "as", "and", "or",
+	"not", "in", "like", "between", "is", "null", "case", "when", "then", "else", "end", "exists", "with", "asc", "desc", "over", "partition",
+	"create", "alter", "drop", "truncate", "table", "view", "index", "primary", "key", "foreign", "references", "default", "check",
+	"unique", "constraint", "grant", "revoke", "true", "false",
+}
+
+var commonReserved = func() map[string]bool {
+	var reserved = make(map[string]bool)
+	for _, keyword := range commonKeywords {
+		reserved[keyword] = true
+	}
+	return reserved
+}()
+
+// 是否为各方言通用的保留关键字
+func isCommonKeyword(word string) bool {
+	return commonReserved[strings.ToLower(word)]
+}
+
+// 各方言通用的内置函数
+var commonFunctions = []string{
+	"count", "sum", "avg", "min", "max", "abs", "ceil", "floor", "round", "mod", "power", "sqrt", "exp", "ln", "sign", "coalesce",
+	"nullif", "cast", "upper", "lower", "trim", "ltrim", "rtrim", "replace", "substring", "length", "concat", "row_number", "rank",
+	"dense_rank", "percent_rank", "cume_dist", "ntile", "lag", "lead", "first_value", "last_value", "nth_value",
+}
+
+var (
+	// MySQL mysql方言
+	MySQL Dialect = newDialect(dialect{
+		name:        "mysql",
+		quotes:      []string{"`"}, // 双引号默认为字符串（未开启ANSI_QUOTES）
+		backslash:   true,
+		placeholder: QuestionStyle,
+		pagination:  LimitCommaPagination,
+		operators:   mysqlOperators,
+		functions: []string{
+			"ifnull", "if", "now", "curdate", "curtime", "sysdate", "date_format", "str_to_date", "date_add", "date_sub", "datediff",
+			"timestampdiff", "unix_timestamp", "from_unixtime", "year", "month", "day", "hour", "minute", "second", "group_concat",
+			"concat_ws", "substr", "substring_index", "char_length", "instr", "locate", "lpad", "rpad", "left", "right", "greatest",
+			"least", "convert", "json_extract", "json_object", "json_array", "uuid", "rand", "last_insert_id", "found_rows",
+		},
+	}, []string{
+		"limit", "offset", "straight_join", "ignore", "replace", "duplicate", "regexp", "rlike", "div", "mod", "xor", "interval",
+		"force", "use", "lock", "unlock", "show", "describe", "explain", "delimiter", "high_priority", "low_priority", "delayed",
+		"sql_calc_found_rows", "sql_no_cache", "sql_small_result", "sql_big_result", "sql_buffer_result",
+	}, LimitClause, TableHintClause, OnDuplicateClause, BooleanClause)
+
+	// PostgreSQL postgresql方言
+	PostgreSQL Dialect = newDialect(dialect{
+		name:        "postgresql",
+		quotes:      []string{`"`},
+		placeholder: DollarStyle,
+		pagination:  LimitOffsetPagination,
+		operators:   postgresOperators,
+		functions: []string{
+			"now", "to_char", "to_date", "to_timestamp", "to_number", "date_trunc", "date_part", "extract", "age", "string_agg",
+			"array_agg", "json_agg", "jsonb_agg", "json_build_object", "jsonb_build_object", "generate_series", "unnest", "substr",
+			"position", "strpos", "split_part", "concat_ws", "left", "right", "lpad", "rpad", "greatest", "least", "random",
+			"gen_random_uuid", "regexp_replace", "nextval", "currval", "setval", "current_setting",
+		},
+	}, []string{
+		"limit", "offset", "fetch", "only", "returning", "ilike", "similar", "lateral", "window", "filter", "analyse", "analyze",
+		"array", "conflict", "nothing", "do",
+	}, LimitClause, OffsetFetchClause, ReturningClause, DistinctOnClause, OnConflictClause, SampleClause, BooleanClause)
+
+	// SQLite sqlite方言
+	SQLite Dialect = newDialect(dialect{
+		name:        "sqlite",
+		quotes:      []string{`"`, "`", "["},
+		placeholder: QuestionStyle,
+		pagination:  LimitOffsetPagination,
+		operators:   []string{"not glob", "glob", "not regexp", "regexp"},
+		functions: []string{
+			"ifnull", "iif", "substr", "instr", "printf", "date", "time", "datetime", "julianday", "strftime", "group_concat", "random",
+			"typeof", "total", "json_extract", "json_object", "json_array", "last_insert_rowid", "changes",
+		},
+	}, []string{
+		"limit", "offset", "glob", "regexp", "autoincrement", "pragma", "vacuum", "returning", "conflict", "replace", "abort", "fail",
+		"ignore", "rollback", "indexed",
+	}, LimitClause, ReturningClause, OnConflictClause, BooleanClause)
+
+	// Oracle oracle方言
+	Oracle Dialect = newDialect(dialect{
+		name:        "oracle",
+		quotes:      []string{`"`},
+		placeholder: ColonStyle,
+		pagination:  OffsetFetchPagination,
+		functions: []string{
+			"nvl", "nvl2", "decode", "to_char", "to_date", "to_number", "to_timestamp", "trunc", "add_months", "months_between",
+			"last_day", "extract", "substr", "instr", "lpad", "rpad", "listagg", "wm_concat", "regexp_like", "regexp_substr",
+			"regexp_replace", "greatest", "least", "sys_guid",
+		},
+	}, []string{
+		"rownum", "rowid", "level", "connect", "start", "prior", "nocycle", "siblings", "minus", "offset", "fetch", "rows", "only",
+		"returning", "sysdate", "dual",
+	}, OffsetFetchClause, RownumClause, ReturningClause, ConnectByClause, SetMinusClause)
+
+	// SQLServer sql server方言
+	SQLServer Dialect = newDialect(dialect{
+		name:        "sqlserver",
+		quotes:      []string{"[", `"`},
+		placeholder: AtStyle,
+		pagination:  OffsetFetchPagination,
+		functions: []string{
+			"isnull", "iif", "choose", "getdate", "getutcdate", "sysdatetime", "dateadd", "datediff", "datepart", "datename", "eomonth",
+			"convert", "try_convert", "try_cast", "len", "charindex", "left", "right", "stuff", "string_agg", "format", "newid",
+			"scope_identity", "object_id",
+		},
+	}, []string{
+		"top", "percent", "ties", "apply", "output", "offset", "fetch", "rows", "only", "declare", "exec", "execute", "print", "pivot",
+		"unpivot", "identity", "nocheck",
+	}, OffsetFetchClause, TopClause, OutputClause, ApplyClause, TableHintClause, SampleClause)
+
+	// Hive hive/spark方言
+	Hive Dialect = newDialect(dialect{
+		name:        "hive",
+		quotes:      []string{"`"},
+		backslash:   true,
+		placeholder: QuestionStyle,
+		pagination:  LimitCommaPagination,
+		operators:   hiveOperators,
+		functions: []string{
+			"nvl", "if", "concat_ws", "collect_list", "collect_set", "explode", "posexplode", "get_json_object", "from_unixtime",
+			"unix_timestamp", "to_date", "date_format", "date_add", "date_sub", "datediff", "year", "month", "day", "substr", "split",
+			"size", "instr", "lpad", "rpad", "regexp_replace", "regexp_extract", "greatest", "least",
+		},
+	}, []string{
+		"limit", "overwrite", "lateral", "partitioned", "clustered", "cluster", "distribute", "sort", "stored", "location",
+		"tblproperties", "tablesample", "external", "row", "format", "semi",
+	}, LimitClause, LateralViewClause, DistributeClause, SampleClause, BooleanClause)
+
+	// ClickHouse clickhouse方言
+	ClickHouse Dialect = newDialect(dialect{
+		name:        "clickhouse",
+		quotes:      []string{"`", `"`},
+		backslash:   true,
+		placeholder: QuestionStyle,
+		pagination:  LimitOffsetPagination,
+		operators:   clickhouseOperators,
+		functions: []string{
+			"if", "multiIf", "ifNull", "toDate", "toDateTime", "toString", "toInt32", "toInt64", "toUInt32", "toUInt64", "toFloat64",
+			"toStartOfDay", "toStartOfMonth", "toYYYYMM", "today", "yesterday", "now", "formatDateTime", "uniq", "uniqExact",
+			"groupArray", "arrayJoin", "arrayMap", "arrayFilter", "has", "any", "argMax", "argMin", "countIf", "sumIf", "avgIf",
+			"quantile", "splitByChar", "JSONExtractString",
+		},
+	}, []string{
+		"limit", "offset", "final", "sample", "prewhere", "array", "global", "any", "asof", "semi", "anti", "settings", "format",
+		"totals", "ilike",
+	}, LimitClause, ArrayJoinClause, PrewhereClause, FinalClause, SampleClause, LimitByClause, SettingsClause, FormatClause, BooleanClause)
+)
+
+// Dialects 全部内置方言
+func Dialects() []Dialect {
+	return []Dialect{MySQL, PostgreSQL, SQLite, Oracle, SQLServer, Hive, ClickHouse}
+}
+
+// DialectOf 根据名称（忽略大小写）获取内置方言，未找到时返回nil
+func DialectOf(name string) Dialect {
+	for _, dialect := range Dialects() {
+		if strings.EqualFold(dialect.Name(), name) {
+			return dialect
+		}
+	}
+	return nil
+}
+
+// 内置方言
+type dialect struct {
+	name        string           // 方言名称
+	keywords    []string         // 保留关键字
+	functions   []string         // 内置函数
+	quotes      []string         // 标识符引号
+	backslash   bool             // 字符串是否支持反斜杠转义
+	placeholder PlaceholderStyle // 绑定占位符风格
+	pagination  Pagination       // 分页语法
+	operators   []string         // 方言特有的条件运算符
+	reserved    map[string]bool  // 保留关键字集合
+	builtins    map[string]bool  // 内置函数集合（小写）
+	clauses     map[Clause]bool  // 支持的子句集合
+}
+
+func newDialect(d dialect, keywords []string, clauses ...Clause) *dialect {
+	d.keywords = append(append([]string{}, commonKeywords...), keywords...)
+	d.reserved = make(map[string]bool)
+	for _, keyword := range d.keywords {
+		d.reserved[keyword] = true
+	}
+	d.functions = append(append([]string{}, commonFunctions...), d.functions...)
+	d.builtins = make(map[string]bool)
+	for _, function := range d.functions {
+		d.builtins[strings.ToLower(function)] = true
+	}
+	d.clauses = make(map[Clause]bool)
+	for _, clause := range clauses {
+		d.clauses[clause] = true
+	}
+	return &d
+}
+
+func (d *dialect) Name() string {
+	return d.name
+}
+
+func (d *dialect) Keywords() []string {
+	return d.keywords
+}
+
+func (d *dialect) IsKeyword(word string) bool {
+	return d.reserved[strings.ToLower(word)]
+}
+
+func (d *dialect) Functions() []string {
+	return d.functions
+}
+
+func (d *dialect) IsFunction(word string) bool {
+	return d.builtins[strings.ToLower(word)]
+}
+
+func (d *dialect) Quotes() []string {
+	return d.quotes
+}
+
+func (d *dialect) QuoteIdentifier(name string) string {
+	switch quote := d.quotes[0]; quote {
+	case "[":
+		return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+	default:
+		return quote + strings.ReplaceAll(name, quote, quote+quote) + quote
+	}
+}
+
+func (d *dialect) EscapeString(value string) string {
+	if d.backslash {
+		value = strings.ReplaceAll(value, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func (d *dialect) UnescapeString(sql string) string {
+	if l := len(sql); l >= 2 && sql[0] == '\'' && sql[l-1] == '\'' {
+		sql = sql[1 : l-1]
+	}
+	if !d.backslash {
+		return strings.ReplaceAll(sql, "''", "'")
+	}
+	var value = strings.Builder{}
+	for i := 0; i < len(sql); i++ {
+		if c := sql[i]; c == '\\' && i+1 < len(sql) {
+			i++
+			switch sql[i] {
+			case 'n':
+				value.WriteByte('\n')
+			case 't':
+				value.WriteByte('\t')
+			case 'r':
+				value.WriteByte('\r')
+			case '0':
+				value.WriteByte(0)
+			default: // \'、\"、\\以及其他字符
+				value.WriteByte(sql[i])
+			}
+		} else if c == '\'' && i+1 < len(sql) && sql[i+1] == '\'' {
+			value.WriteByte(c)
+			i++
+		} else {
+			value.WriteByte(c)
+		}
+	}
+	return value.String()
+}
+
+func (d *dialect) PlaceholderStyle() PlaceholderStyle {
+	return d.placeholder
+}
+
+func (d *dialect) Placeholder(position int) string {
+	switch d.placeholder {
+	case DollarStyle:
+		return "$" + strconv.Itoa(position)
+	case ColonStyle:
+		return ":" + strconv.Itoa(position)
+	case AtStyle:
+		return "@p" + strconv.Itoa(position)
+	default:
+		return string(QuestionStyle)
+	}
+}
+
+func (d *dialect) Pagination() Pagination {
+	return d.pagination
+}
+
+func (d *dialect) Supports(clause Clause) bool {
+	return d.clauses[clause]
+}
+
+func (d *dialect) Operators() []string {
+	return d.operators
+}
+
+// 未指定方言时识别的条件运算符：全部内置方言的运算符，排除与绑定占位符混淆的?、?|、?&
+var genericOperators = func() []string {
+	var operators []string
+	var exists = map[string]bool{"?": true, "?|": true, "?&": true}
+	for _, dialect := range Dialects() {
+		for _, operator := range dialect.Operators() {
+			if !exists[operator] {
+				exists[operator] = true
+				operators = append(operators, operator)
+			}
+		}
+	}
+	return operators
+}()
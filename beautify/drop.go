@@ -0,0 +1,132 @@
+package beautify
+
+import (
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+	"github.com/go-xuan/sqlx/utils"
+)
+
+// ParseDropSQL 解析删除对象SQL（drop table、drop index、drop view）
+func ParseDropSQL(sql string, indent ...int) *Drop {
+	// sql初始化
+	var parser = &Drop{
+		Base: NewBase(sql, indent...),
+	}
+
+	// sql解析
+	parser.parsePrepare() // 解析准备
+	parser.parseObject()  // 解析删除对象
+	parser.parseFinish()  // 解析完成
+
+	return parser
+}
+
+type Drop struct {
+	Base
+	Object       string   // 删除对象类型（table、index、view、materialized view）
+	Temporary    bool     // 是否临时表（mysql：drop temporary table）
+	Concurrently bool     // 是否concurrently（postgresql：drop index concurrently）
+	IfExists     bool     // 是否if exists
+	Names        []string // 删除对象名称
+	Table        *Table   // 索引所属表（mysql：drop index idx on t）
+	Option       string   // 删除选项（cascade、restrict）
+}
+
+// Beautify SQL美化输出
+func (x *Drop) Beautify() string {
+	var sql = strings.Builder{}
+	sql.WriteString(consts.DROP)
+	sql.WriteString(consts.Blank)
+	if x.Temporary {
+		sql.WriteString(consts.TEMPORARY)
+		sql.WriteString(consts.Blank)
+	}
+	sql.WriteString(x.Object)
+	if x.Concurrently {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.CONCURRENTLY)
+	}
+	if x.IfExists {
+		sql.WriteString(" if exists")
+	}
+	sql.WriteString(consts.Blank)
+	sql.WriteString(strings.Join(x.Names, ", "))
+	if x.Table != nil {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.ON)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(x.Table.beautify())
+	}
+	if x.Option != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(x.Option)
+	}
+	return x.finish(sql.String())
+}
+
+// Tables 删除语句涉及的表
+func (x *Drop) Tables() []*Table {
+	if x.Object == consts.INDEX {
+		if x.Table != nil {
+			return []*Table{x.Table}
+		}
+		return nil
+	}
+	var tables []*Table
+	for _, name := range x.Names {
+		tables = append(tables, NewTable(name))
+	}
+	return tables
+}
+
+// 提取删除对象
+func (x *Drop) parseObject() *Drop {
+	sql := strings.TrimSuffix(strings.TrimSpace(x.tempSql), consts.Semicolon)
+	words := utils.SplitFieldsExcludeInBracket(sql)
+	var i = 1 // 跳过drop
+	// 修饰词，例如drop temporary table
+	if i < len(words) && strings.EqualFold(words[i], consts.TEMPORARY) {
+		x.Temporary = true
+		i++
+	}
+	if i >= len(words) {
+		panic("当前输入sql无法解析 " + x.originSql)
+	}
+	x.Object = strings.ToLower(words[i])
+	if x.Object == consts.MATERIALIZED && i+1 < len(words) { // drop materialized view
+		i++
+		x.Object = x.Object + consts.Blank + strings.ToLower(words[i])
+	}
+	for i++; i < len(words); { // postgresql：concurrently与if exists先后顺序不限
+		if i+1 < len(words) && strings.EqualFold(words[i], consts.IF) && strings.EqualFold(words[i+1], consts.EXISTS) {
+			x.IfExists = true
+			i += 2
+		} else if strings.EqualFold(words[i], consts.CONCURRENTLY) {
+			x.Concurrently = true
+			i++
+		} else {
+			break
+		}
+	}
+	words = words[i:]
+	// 删除选项
+	if l := len(words); l > 0 && (strings.EqualFold(words[l-1], consts.CASCADE) || strings.EqualFold(words[l-1], consts.RESTRICT)) {
+		x.Option, words = strings.ToLower(words[l-1]), words[:l-1]
+	}
+	// mysql：drop index idx on t
+	for j, word := range words {
+		if strings.EqualFold(word, consts.ON) && j+1 < len(words) {
+			x.Table, words = NewTable(words[j+1]), words[:j]
+			break
+		}
+	}
+	sql = strings.Join(words, consts.Blank)
+	list, last := utils.SplitExcludeInBracket(sql, consts.Comma)
+	for _, name := range append(list, last) {
+		if name = strings.TrimSpace(name); name != consts.Empty {
+			x.Names = append(x.Names, name)
+		}
+	}
+	return x
+}
@@ -0,0 +1,283 @@
+package beautify
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+	"github.com/go-xuan/sqlx/utils"
+)
+
+// LiteralType 字面量类型
+type LiteralType string
+
+const (
+	StringLiteral   LiteralType = "string"   // 字符串：'abc'、n'abc'
+	NumberLiteral   LiteralType = "number"   // 数值：1、-1.5、1e10
+	BooleanLiteral  LiteralType = "boolean"  // 布尔：true、false
+	NullLiteral     LiteralType = "null"     // 空值：null
+	DateTimeLiteral LiteralType = "datetime" // 日期时间：date '2024-01-01'、timestamp with time zone '...'
+	IntervalLiteral LiteralType = "interval" // 时间间隔：interval '1 day'、interval '1' day
+	HexLiteral      LiteralType = "hex"      // 十六进制：x'FF'、0xFF
+	BitLiteral      LiteralType = "bit"      // 二进制：b'101'、0b101
+)
+
+// NewExpression 解析表达式中的字面量、类型转换以及排序规则，均不匹配时返回nil
+func NewExpression(sql string) *Expression {
+	sql = strings.TrimSpace(sql)
+	var expr = &Expression{Text: sql}
+	if match := collatePattern.FindStringSubmatchIndex(sql); match != nil {
+		expr.Collate = sql[match[2]:match[3]]
+		sql = strings.TrimSpace(sql[:match[0]])
+		expr.Text = sql
+	}
+	if expr.Cast = NewCast(sql); expr.Cast == nil {
+		expr.Literal = NewLiteral(sql)
+	}
+	if expr.Collate == consts.Empty && expr.Cast == nil && expr.Literal == nil {
+		return nil
+	}
+	return expr
+}
+
+// Expression 表达式解析
+type Expression struct {
+	Text    string   // 表达式原文（不包含排序规则）
+	Literal *Literal // 字面量
+	Cast    *Cast    // 类型转换
+	Collate string   // 排序规则（collate utf8mb4_bin）
+	Span    Span     // 原始sql中的位置
+}
+
+func (e *Expression) beautify() string {
+	var sql = strings.Builder{}
+	if e.Cast != nil {
+		sql.WriteString(e.Cast.beautify())
+	} else if e.Literal != nil {
+		sql.WriteString(e.Literal.beautify())
+	} else {
+		sql.WriteString(e.Text)
+	}
+	if e.Collate != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.COLLATE)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(e.Collate)
+	}
+	return sql.String()
+}
+
+// NewLiteral 解析字面量，非字面量时返回nil
+func NewLiteral(sql string) *Literal {
+	sql = strings.TrimSpace(sql)
+	lower := strings.ToLower(sql)
+	switch {
+	case lower == "true" || lower == "false":
+		return &Literal{Type: BooleanLiteral, Value: lower}
+	case lower == consts.NULL:
+		return &Literal{Type: NullLiteral, Value: lower}
+	case numberPattern.MatchString(sql):
+		return &Literal{Type: NumberLiteral, Value: sql}
+	case stringPattern.MatchString(sql):
+		return &Literal{Type: StringLiteral, Value: sql}
+	case strings.HasPrefix(lower, "0x") && hexPattern.MatchString(sql[2:]):
+		return &Literal{Type: HexLiteral, Value: sql}
+	case strings.HasPrefix(lower, "0b") && strings.Trim(sql[2:], "01") == consts.Empty && len(sql) > 2:
+		return &Literal{Type: BitLiteral, Value: sql}
+	case prefixedPattern.MatchString(sql): // x'FF'、b'101'、n'abc'、e'abc'，前缀保持原始写法
+		switch lower[:1] {
+		case "x":
+			return &Literal{Type: HexLiteral, Value: sql}
+		case "b":
+			return &Literal{Type: BitLiteral, Value: sql}
+		default:
+			return &Literal{Type: StringLiteral, Value: sql}
+		}
+	}
+	if match := typedPattern.FindStringSubmatch(sql); match != nil { // date '2024-01-01'、interval '1' day
+		var literal = &Literal{Type: DateTimeLiteral, TypeName: strings.ToLower(strings.Join(strings.Fields(match[1]), consts.Blank)), Value: match[2]}
+		if literal.TypeName == "interval" {
+			literal.Type = IntervalLiteral
+			if unit := strings.TrimSpace(match[3]); unit != consts.Empty {
+				literal.Unit = strings.ToLower(strings.Join(strings.Fields(unit), consts.Blank))
+			}
+		} else if match[3] != consts.Empty {
+			return nil
+		}
+		return literal
+	}
+	return nil
+}
+
+// Literal 字面量
+type Literal struct {
+	Type     LiteralType // 字面量类型
+	TypeName string      // 类型前缀（date、time、timestamp、timestamp with time zone、interval），仅日期时间以及时间间隔
+	Value    string      // 字面量原文
+	Unit     string      // 时间间隔单位（interval '1' day、interval '1-2' year to month）
+}
+
+func (l *Literal) beautify() string {
+	var sql = strings.Builder{}
+	if l.TypeName != consts.Empty {
+		sql.WriteString(l.TypeName)
+		sql.WriteString(consts.Blank)
+	}
+	sql.WriteString(l.Value)
+	if l.Unit != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(l.Unit)
+	}
+	return sql.String()
+}
+
+// NewCast 解析类型转换（cast(a as int)、a::int、convert(a, char)），非类型转换时返回nil
+func NewCast(sql string) *Cast {
+	sql = strings.TrimSpace(sql)
+	lower := strings.ToLower(sql)
+	if match := castPattern.FindStringSubmatch(lower); match != nil {
+		if from, to := utils.BetweenOfString(sql, consts.LeftBracket, consts.RightBracket); from == len(match[0])-1 && to == len(sql)-1 {
+			args := sql[from+1 : to]
+			if match[1] == "convert" {
+				list, last := utils.SplitExcludeInBracket(args, consts.Comma)
+				if args := append(list, last); len(args) > 1 && len(args) <= 3 && isTypeName(args[0]) && !isTypeName(args[1]) {
+					// sql server：convert(varchar(10), a[, style])
+					var cast = &Cast{Style: match[1], Type: castType(args[0]), Expr: strings.TrimSpace(args[1]), typeFirst: true}
+					if len(args) == 3 {
+						cast.Format = strings.TrimSpace(args[2])
+					}
+					return cast
+				} else if len(list) == 1 { // mysql：convert(a, char)
+					return &Cast{Style: match[1], Expr: strings.TrimSpace(list[0]), Type: castType(last)}
+				}
+			} else if index := lastIndexExcludeBrackets(strings.ToLower(args), consts.Blank+consts.AS+consts.Blank); index > 0 {
+				return &Cast{Style: match[1], Expr: strings.TrimSpace(args[:index]), Type: castType(args[index+4:])}
+			}
+		}
+		return nil
+	}
+	// postgresql：a::int、'1'::numeric(10,2)、a::text::int
+	if index := lastIndexExcludeBrackets(sql, "::"); index > 0 {
+		expr := strings.TrimSpace(sql[:index])
+		if len(utils.SplitFieldsExcludeInBracket(expr)) == 1 || NewLiteral(expr) != nil {
+			return &Cast{Style: "::", Expr: expr, Type: castType(sql[index+2:])}
+		}
+	}
+	return nil
+}
+
+// Cast 类型转换
+type Cast struct {
+	Style     string // 转换写法（cast、try_cast、safe_cast、convert、::）
+	Expr      string // 被转换表达式
+	Type      string // 目标类型
+	Format    string // sql server：convert的样式参数（convert(varchar, a, 120)）
+	typeFirst bool   // sql server：convert的目标类型在被转换表达式之前
+}
+
+func (c *Cast) beautify() string {
+	var sql = strings.Builder{}
+	expr := c.Expr
+	if inner := NewExpression(expr); inner != nil {
+		expr = inner.beautify()
+	}
+	switch c.Style {
+	case "::":
+		sql.WriteString(expr)
+		sql.WriteString("::")
+		sql.WriteString(c.Type)
+	case "convert":
+		sql.WriteString(c.Style)
+		sql.WriteString(consts.LeftBracket)
+		if c.typeFirst {
+			sql.WriteString(c.Type)
+			sql.WriteString(", ")
+			sql.WriteString(expr)
+		} else {
+			sql.WriteString(expr)
+			sql.WriteString(", ")
+			sql.WriteString(c.Type)
+		}
+		if c.Format != consts.Empty {
+			sql.WriteString(", ")
+			sql.WriteString(c.Format)
+		}
+		sql.WriteString(consts.RightBracket)
+	default:
+		sql.WriteString(c.Style)
+		sql.WriteString(consts.LeftBracket)
+		sql.WriteString(expr)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.AS)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(c.Type)
+		sql.WriteString(consts.RightBracket)
+	}
+	return sql.String()
+}
+
+// 规范类型写法：内置类型名转为小写，自定义类型以及引用的类型名保持原始写法，精度参数以", "分隔（decimal(10, 2)）
+func castType(sql string) string {
+	var words = strings.Fields(sql)
+	for i, word := range words {
+		if lower := strings.ToLower(word); isTypeName(lower) || caseKeywords[lower] {
+			words[i] = lower
+		} else if name, args := utils.CutString(word, consts.LeftBracket); args != consts.Empty && (isTypeName(name) || caseKeywords[strings.ToLower(name)]) {
+			words[i] = strings.ToLower(name) + consts.LeftBracket + args
+		}
+	}
+	sql = strings.Join(words, consts.Blank)
+	if from, to := utils.BetweenOfString(sql, consts.LeftBracket, consts.RightBracket); from > 0 && to > from {
+		list, last := utils.SplitExcludeInBracket(sql[from+1:to], consts.Comma)
+		for i := range list {
+			list[i] = strings.TrimSpace(list[i])
+		}
+		sql = strings.TrimSpace(sql[:from]) + consts.LeftBracket + strings.Join(append(list, strings.TrimSpace(last)), ", ") + consts.RightBracket + sql[to+1:]
+	}
+	return sql
+}
+
+// 是否内置数据类型名（sql server的convert以此区分目标类型与被转换表达式）
+func isTypeName(sql string) bool {
+	name, _ := utils.CutString(strings.TrimSpace(sql), consts.LeftBracket)
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "char", "varchar", "nchar", "nvarchar", "text", "ntext", "binary", "varbinary", "int", "bigint", "smallint", "tinyint",
+		"bit", "decimal", "numeric", "money", "smallmoney", "float", "real", "date", "time", "datetime", "datetime2",
+		"smalldatetime", "datetimeoffset", "uniqueidentifier", "xml", "sql_variant":
+		return true
+	default:
+		return false
+	}
+}
+
+// 获取key末次出现的下标，排除括号以及引号内的内容
+func lastIndexExcludeBrackets(sql, key string) int {
+	var last, brackets = -1, 0
+	for i := 0; i < len(sql); i++ {
+		switch c := sql[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			if end := strings.IndexByte(sql[i+1:], c); end >= 0 {
+				i += end + 1
+			}
+		case c == '(':
+			brackets++
+		case c == ')' && brackets > 0:
+			brackets--
+		case brackets == 0 && strings.HasPrefix(sql[i:], key):
+			last = i
+			i += len(key) - 1
+		}
+	}
+	return last
+}
+
+var (
+	literalValue    = `(` + consts.ReplacePrefix + `\d+` + consts.ReplaceSuffix + `|'[^']*')`
+	numberPattern   = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+	stringPattern   = regexp.MustCompile(`^` + literalValue + `$`)
+	hexPattern      = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+	prefixedPattern = regexp.MustCompile(`^[xXbBnNeE]` + literalValue + `$`)
+	typedPattern    = regexp.MustCompile(`(?i)^(date|time|timestamp|datetime|timestamp\s+with(?:out)?\s+time\s+zone|time\s+with(?:out)?\s+time\s+zone|interval)\s+` + literalValue + `((?:\s+(?:year|month|week|day|hour|minute|second|quarter|to)s?)*)$`)
+	castPattern     = regexp.MustCompile(`^(cast|try_cast|safe_cast|convert)\s*\(`)
+	collatePattern  = regexp.MustCompile(`(?i)\s+collate\s+([\w"` + "`" + `.]+)$`)
+)
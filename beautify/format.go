@@ -0,0 +1,301 @@
+package beautify
+
+import (
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+)
+
+// AliasStyle 别名as风格
+type AliasStyle string
+
+const (
+	AliasDefault AliasStyle = ""       // 默认：字段别名省略as，查询中的表别名使用as
+	AliasAlways  AliasStyle = "always" // 字段别名以及表别名均使用as
+	AliasNever   AliasStyle = "never"  // 字段别名以及表别名均省略as
+)
+
+// SemicolonStyle 语句末尾分号风格
+type SemicolonStyle string
+
+const (
+	SemicolonDefault SemicolonStyle = ""       // 默认：单条语句不追加分号，脚本中每条语句追加分号
+	SemicolonAlways  SemicolonStyle = "always" // 每条语句末尾均追加分号
+	SemicolonNever   SemicolonStyle = "never"  // 语句末尾均不追加分号
+)
+
+// 默认格式化参数
+const (
+	defaultIndent          = 6   // 关键字对齐宽度
+	defaultMaxLineWidth    = 100 // group by、order by列表最大行宽
+	defaultSelectWrap      = 6   // 查询字段换行数量
+	defaultInWrap          = 3   // in列表换行数量
+	defaultInsertWrapWidth = 120 // 插入字段换行宽度
+	defaultInsertWrapCount = 10  // 插入字段换行数量
+	defaultTabWidth        = 4   // 制表符宽度
+)
+
+// FormatOptions 格式化选项，数值为0时使用默认值
+type FormatOptions struct {
+	Indent          int            // 关键字右对齐宽度，不小于6（select、update等关键字的长度），默认6
+	MaxLineWidth    int            // group by、order by列表的最大行宽，超过时逐项换行，默认100
+	SelectWrap      int            // 查询字段数量达到该值时逐个换行（存在字段别名时始终换行），默认6
+	InWrap          int            // in列表值数量超过该值时逐个换行，默认3
+	InsertWrapWidth int            // 插入字段总宽度超过该值时逐个换行，默认120
+	InsertWrapCount int            // 插入字段数量超过该值时逐个换行，默认10
+	UseTabs         bool           // 行首缩进使用制表符，不足一个制表符的部分仍使用空格
+	TabWidth        int            // 制表符宽度，默认4
+	Alias           AliasStyle     // 别名as风格
+	Semicolon       SemicolonStyle // 语句末尾分号风格
+	KeywordCase     CaseStyle      // 关键字大小写（upper、lower、preserve）
+	FunctionCase    CaseStyle      // 内置函数名大小写（upper、lower、preserve），按方言的函数目录识别
+	IdentifierCase  CaseStyle      // 未引用标识符大小写（preserve、lower），引号内的标识符保持不变
+}
+
+// DefaultFormatOptions 默认格式化选项
+func DefaultFormatOptions() *FormatOptions {
+	return &FormatOptions{
+		Indent:          defaultIndent,
+		MaxLineWidth:    defaultMaxLineWidth,
+		SelectWrap:      defaultSelectWrap,
+		InWrap:          defaultInWrap,
+		InsertWrapWidth: defaultInsertWrapWidth,
+		InsertWrapCount: defaultInsertWrapCount,
+		TabWidth:        defaultTabWidth,
+	}
+}
+
+var defaultFormat = DefaultFormatOptions()
+
+// 补全默认值
+func (f *FormatOptions) withDefaults() *FormatOptions {
+	if f == nil {
+		return defaultFormat
+	}
+	var format = *f
+	for _, option := range []struct {
+		value        *int
+		defaultValue int
+	}{
+		{&format.Indent, defaultIndent},
+		{&format.MaxLineWidth, defaultMaxLineWidth},
+		{&format.SelectWrap, defaultSelectWrap},
+		{&format.InWrap, defaultInWrap},
+		{&format.InsertWrapWidth, defaultInsertWrapWidth},
+		{&format.InsertWrapCount, defaultInsertWrapCount},
+		{&format.TabWidth, defaultTabWidth},
+	} {
+		if *option.value <= 0 {
+			*option.value = option.defaultValue
+		}
+	}
+	if format.Indent < defaultIndent {
+		format.Indent = defaultIndent
+	}
+	return &format
+}
+
+// 别名是否使用as，defaultAs为默认风格下是否使用
+func (f *FormatOptions) aliasAs(defaultAs bool) bool {
+	switch f.Alias {
+	case AliasAlways:
+		return true
+	case AliasNever:
+		return false
+	default:
+		return defaultAs
+	}
+}
+
+// 行首缩进转换为制表符
+func (f *FormatOptions) indentTabs(sql string) string {
+	if !f.UseTabs {
+		return sql
+	}
+	lines := strings.Split(sql, consts.NextLine)
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, consts.Blank)
+		if blank := len(line) - len(trimmed); blank >= f.TabWidth {
+			lines[i] = strings.Repeat("\t", blank/f.TabWidth) + strings.Repeat(consts.Blank, blank%f.TabWidth) + trimmed
+		}
+	}
+	return strings.Join(lines, consts.NextLine)
+}
+
+// 按分号风格处理语句末尾分号，script表示是否脚本中的语句
+func (f *FormatOptions) terminate(sql string, script bool) string {
+	sql = strings.TrimRight(sql, consts.Blank+consts.NextLine)
+	switch {
+	case f.Semicolon == SemicolonNever:
+		return strings.TrimRight(sql, consts.Semicolon)
+	case f.Semicolon == SemicolonAlways || script:
+		if !strings.HasSuffix(sql, consts.Semicolon) {
+			sql += consts.Semicolon
+		}
+	}
+	return sql
+}
+
+// 可设置格式化选项的语句，level为嵌套层级（最外层为0）
+type formattable interface {
+	setFormat(format *FormatOptions, level int)
+}
+
+// Format 按格式化选项美化已解析的语句，适用于全部语句类型
+func Format(parser IParser, format *FormatOptions) string {
+	applyFormat(parser, format, consts.Empty)
+	return parser.Beautify()
+}
+
+// 为语句及其嵌套的子查询设置格式化选项，并标记为最外层语句，source为保留大小写时参照的原始sql
+func applyFormat(parser IParser, format *FormatOptions, source string) {
+	if x, ok := parser.(formattable); ok {
+		x.setFormat(format.withDefaults(), 0)
+	}
+	if x, ok := parser.(interface{ setRoot(source string) }); ok {
+		x.setRoot(source)
+	}
+}
+
+// 格式化选项，未设置时为默认选项
+func (b *Base) format() *FormatOptions {
+	if b.formatOptions == nil {
+		return defaultFormat
+	}
+	return b.formatOptions
+}
+
+func (b *Base) setFormat(format *FormatOptions, level int) {
+	b.formatOptions = format
+	b.indent = b.parseIndent + format.Indent - defaultIndent // 外层语句的续行按对齐宽度的差值偏移，子查询随之整体偏移
+	b.nested = level > 0
+}
+
+func (b *Base) setRoot(source string) {
+	if source == consts.Empty {
+		source = b.originSql
+	}
+	b.root, b.source = true, source
+}
+
+// 美化完成：还原变量值，最外层语句按格式化选项转换大小写、行首缩进以及处理末尾分号，body为需要保留原文的函数体
+func (b *Base) finish(sql string, body ...string) string {
+	if b.replacer != nil {
+		sql = b.replacer.Replace(sql)
+	}
+	if !b.root {
+		return strings.Join(append([]string{sql}, body...), consts.NextLine)
+	}
+	sql = b.format().convertCase(sql, b.source, b.dialect)
+	sql = strings.Join(append([]string{b.format().indentTabs(sql)}, body...), consts.NextLine)
+	return b.format().terminate(sql, false)
+}
+
+// 为条件及其子查询设置格式化选项
+func formatConditions(conditions []*Condition, format *FormatOptions, level int) {
+	for _, c := range conditions {
+		c.format = format
+		formatConditions(c.Conditions, format, level)
+		if c.Select != nil {
+			c.Select.setFormat(format, level+1)
+		}
+	}
+}
+
+// 为表子查询设置格式化选项
+func (p *Table) setFormat(format *FormatOptions, level int) {
+	if p != nil && p.Select != nil {
+		p.Select.setFormat(format, level+1)
+	}
+}
+
+func (x *Select) setFormat(format *FormatOptions, level int) {
+	x.Base.setFormat(format, level)
+	x.Table.setFormat(format, level)
+	for _, join := range x.Joins {
+		join.Table.setFormat(format, level)
+	}
+	for _, conditions := range [][]*Condition{x.Prewhere, x.Where, x.StartWith, x.ConnectBy, x.Having} {
+		formatConditions(conditions, format, level)
+	}
+	for _, operation := range x.SetOperations { // 集合运算的查询与当前查询同级
+		operation.Select.setFormat(format, level)
+	}
+}
+
+func (x *Insert) setFormat(format *FormatOptions, level int) {
+	x.Base.setFormat(format, level)
+	if x.Query != nil {
+		x.Query.setFormat(format, level)
+	}
+}
+
+func (x *Update) setFormat(format *FormatOptions, level int) {
+	x.Base.setFormat(format, level)
+	formatConditions(x.Where, format, level)
+}
+
+func (x *Delete) setFormat(format *FormatOptions, level int) {
+	x.Base.setFormat(format, level)
+	formatConditions(x.Where, format, level)
+}
+
+func (x *Merge) setFormat(format *FormatOptions, level int) {
+	x.Base.setFormat(format, level)
+	x.Source.setFormat(format, level)
+	formatConditions(x.On, format, level)
+	for _, branch := range x.Branches {
+		for _, conditions := range [][]*Condition{branch.Condition, branch.Where, branch.Delete} {
+			formatConditions(conditions, format, level)
+		}
+	}
+}
+
+func (x *CreateTable) setFormat(format *FormatOptions, level int) {
+	x.Base.setFormat(format, level)
+	if x.Query != nil {
+		x.Query.setFormat(format, level)
+	}
+}
+
+func (x *CreateView) setFormat(format *FormatOptions, level int) {
+	x.Base.setFormat(format, level)
+	if x.Query != nil {
+		x.Query.setFormat(format, level)
+	}
+}
+
+func (x *CreateIndex) setFormat(format *FormatOptions, level int) {
+	x.Base.setFormat(format, level)
+	formatConditions(x.Where, format, level)
+}
+
+func (x *Show) setFormat(format *FormatOptions, level int) {
+	x.Base.setFormat(format, level)
+	formatConditions(x.Where, format, level)
+}
+
+func (x *Explain) setFormat(format *FormatOptions, level int) {
+	x.Base.setFormat(format, level)
+	if statement, ok := x.Statement.(formattable); ok {
+		statement.setFormat(format, level)
+	}
+}
+
+func (p *Partial) setFormat(format *FormatOptions, level int) {
+	p.format = format
+	if statement, ok := p.Statement.(formattable); ok {
+		statement.setFormat(format, level)
+	}
+}
+
+func (p *Partial) setRoot(source string) {
+	if source == consts.Empty {
+		var texts []string
+		for _, segment := range p.segments {
+			texts = append(texts, segment.text)
+		}
+		source = strings.Join(texts, consts.Blank)
+	}
+	p.root, p.source = true, source
+}
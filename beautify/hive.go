@@ -0,0 +1,275 @@
+package beautify
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+	"github.com/go-xuan/sqlx/utils"
+)
+
+var (
+	tableSamplePattern = regexp.MustCompile(`(?i)\s+tablesample\s*\(`) // tablesample (bucket 1 out of 4 on id)
+	lateralViewPattern = regexp.MustCompile(`(?i)^lateral\s+view\s+(outer\s+)?`)
+)
+
+// hive条件运算符，按顺序匹配
+var hiveOperators = []string{"<=>", "not rlike", "rlike", "not regexp", "regexp"}
+
+// hive分发排序子句，按输出顺序排列
+var distributions = []string{"cluster by", "distribute by", "sort by"}
+
+// hive建表子句，按输出顺序排列
+var hiveTableClauses = []string{"partitioned by", "clustered by", "row format", "stored as", "location", "tblproperties"}
+
+// LateralView hive/spark侧视图：lateral view [outer] explode(x) t as a, b
+type LateralView struct {
+	Outer    bool     // 是否lateral view outer
+	Function string   // 表生成函数（explode(x)、posexplode(x)）
+	Alias    string   // 虚拟表别名
+	Columns  []string // 列别名
+}
+
+func (v *LateralView) beautify() string {
+	var sql = strings.Builder{}
+	sql.WriteString("lateral view ")
+	if v.Outer {
+		sql.WriteString(consts.OUTER)
+		sql.WriteString(consts.Blank)
+	}
+	sql.WriteString(v.Function)
+	if v.Alias != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(v.Alias)
+	}
+	if len(v.Columns) > 0 {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.AS)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(strings.Join(v.Columns, ", "))
+	}
+	return sql.String()
+}
+
+// 提取侧视图（lateral view explode(x) t as c），仅处理当前查询层级
+func (x *Select) parseLateralViews() *Select {
+	if !x.supports(LateralViewClause) {
+		return x
+	}
+	sql := x.tempSql
+	for {
+		lower := strings.ToLower(sql)
+		index := utils.IndexExcludeBrackets(lower, " lateral view ", false)
+		if index < 0 {
+			break
+		}
+		rest, end := sql[index+1:], len(sql)-index-1
+		if _, i := utils.ContainsKeywords(lower[index+14:], "lateral", consts.LEFT, consts.RIGHT, consts.INNER, consts.CROSS, consts.JOIN,
+			consts.WHERE, consts.GROUPBY, consts.HAVING, consts.ORDERBY, consts.LIMIT, "cluster by", "distribute by", "sort by"); i >= 0 {
+			end = i + 13
+		}
+		x.LateralViews = append(x.LateralViews, newLateralView(strings.TrimSpace(rest[:end])))
+		if end == len(rest) {
+			sql = sql[:index]
+		} else {
+			sql = sql[:index] + sql[index+end:]
+		}
+	}
+	x.tempSql = sql
+	return x
+}
+
+// 解析单个侧视图
+func newLateralView(sql string) *LateralView {
+	var view = &LateralView{}
+	if match := lateralViewPattern.FindString(sql); match != consts.Empty {
+		view.Outer = strings.TrimSpace(match[12:]) != consts.Empty
+		sql = sql[len(match):]
+	}
+	if _, to := utils.BetweenOfString(sql, consts.LeftBracket, consts.RightBracket); to > 0 {
+		view.Function, sql = sql[:to+1], strings.TrimSpace(sql[to+1:])
+	} else {
+		panic("当前输入sql无法解析 " + sql)
+	}
+	if alias, columns := utils.CutString(sql, consts.Blank); alias != consts.Empty {
+		view.Alias = alias
+		if columns = strings.TrimSpace(columns); strings.HasPrefix(strings.ToLower(columns), consts.AS+consts.Blank) {
+			columns = columns[3:]
+		}
+		for _, column := range strings.Split(columns, consts.Comma) {
+			if column = strings.TrimSpace(column); column != consts.Empty {
+				view.Columns = append(view.Columns, column)
+			}
+		}
+	}
+	return view
+}
+
+func (x *Select) beautifyLateralViews() string {
+	sql := strings.Builder{}
+	for _, view := range x.LateralViews {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(x.align(view.beautify()))
+	}
+	return sql.String()
+}
+
+// 提取cluster by、distribute by、sort by，仅处理当前查询层级
+func (x *Select) parseDistributions() *Select {
+	if !x.supports(DistributeClause) {
+		return x
+	}
+	sql := x.tempSql
+	lower := strings.ToLower(sql)
+	var starts = make(map[string]int)
+	var first = len(sql)
+	for _, key := range distributions {
+		if index := utils.IndexExcludeBrackets(lower, key, true); index > 0 {
+			starts[key] = index
+			if index < first {
+				first = index
+			}
+		}
+	}
+	for key, start := range starts {
+		var end = len(sql)
+		for _, other := range starts {
+			if other > start && other < end {
+				end = other
+			}
+		}
+		list, last := utils.SplitExcludeInBracket(sql[start+len(key):end], consts.Comma)
+		var values []string
+		for _, value := range append(list, last) {
+			values = append(values, strings.TrimSpace(value))
+		}
+		switch key {
+		case "cluster by":
+			x.ClusterBy = values
+		case "distribute by":
+			x.DistributeBy = values
+		case "sort by":
+			x.SortBy = values
+		}
+	}
+	x.tempSql = sql[:first]
+	return x
+}
+
+func (x *Select) beautifyDistributions() string {
+	sql := strings.Builder{}
+	for i, values := range [][]string{x.ClusterBy, x.DistributeBy, x.SortBy} {
+		if len(values) > 0 {
+			sql.WriteString(consts.NextLine)
+			sql.WriteString(x.align(distributions[i]))
+			sql.WriteString(consts.Blank)
+			sql.WriteString(strings.Join(values, ", "))
+		}
+	}
+	return sql.String()
+}
+
+// 提取表采样（tablesample (bucket 1 out of 4 on id)、tablesample (10 percent)）并按紧邻的表名归类，返回去除表采样之后的sql
+func extractTableSamples(sql string, samples map[string]string) string {
+	matches := tableSamplePattern.FindAllStringIndex(sql, -1)
+	for i := len(matches) - 1; i >= 0; i-- { // 倒序处理，避免截取之后下标失效
+		match := matches[i]
+		if prefix := sql[:match[0]]; strings.Count(prefix, consts.LeftBracket) != strings.Count(prefix, consts.RightBracket) {
+			continue
+		}
+		if from, to := utils.BetweenOfString(sql[match[1]-1:], consts.LeftBracket, consts.RightBracket); from == 0 && to > 0 {
+			if words := strings.Fields(sql[:match[0]]); len(words) > 0 {
+				args := strings.Join(strings.Fields(sql[match[1]:match[1]-1+to]), consts.Blank)
+				samples[words[len(words)-1]] = "tablesample (" + args + consts.RightBracket
+			}
+			sql = sql[:match[0]] + sql[match[1]+to:]
+		}
+	}
+	return sql
+}
+
+// 提取hive建表子句（partitioned by、clustered by、row format、stored as、location、tblproperties），返回剩余的表选项sql
+func (x *CreateTable) parseHiveClauses(sql string, option *TableOption) string {
+	lower := strings.ToLower(sql)
+	var starts = make(map[string]int)
+	for _, key := range hiveTableClauses {
+		if index := utils.IndexExcludeBrackets(consts.Blank+lower+consts.Blank, consts.Blank+key+consts.Blank, false); index >= 0 {
+			starts[key] = index
+		} else if index = utils.IndexExcludeBrackets(consts.Blank+lower, consts.Blank+key+consts.LeftBracket, false); index >= 0 {
+			starts[key] = index // tblproperties('k'='v')
+		}
+	}
+	if len(starts) == 0 {
+		return sql
+	}
+	var first = len(sql)
+	for key, start := range starts {
+		var end = len(sql)
+		for _, other := range starts {
+			if other > start && other < end {
+				end = other
+			}
+		}
+		if start < first {
+			first = start
+		}
+		raw := strings.TrimSpace(sql[start+len(key) : end])
+		value := x.restore(raw)
+		switch key {
+		case "partitioned by":
+			list, last := utils.SplitExcludeInBracket(utils.TrimBrackets(raw), consts.Comma)
+			for _, column := range append(list, last) {
+				if words := utils.SplitFieldsExcludeInBracket(column); len(words) > 0 {
+					option.PartitionedBy = append(option.PartitionedBy, x.newColumn(words))
+				}
+			}
+		case "clustered by":
+			option.ClusteredBy = value
+		case "row format":
+			option.RowFormat = value
+		case "stored as":
+			option.StoredAs = value
+		case "location":
+			option.Location = value
+		case "tblproperties":
+			option.Properties = value
+		}
+	}
+	return strings.TrimSpace(sql[:first])
+}
+
+// 是否包含hive建表子句
+func (o *TableOption) isHive() bool {
+	return len(o.PartitionedBy) > 0 || o.ClusteredBy != consts.Empty || o.RowFormat != consts.Empty ||
+		o.StoredAs != consts.Empty || o.Location != consts.Empty || o.Properties != consts.Empty
+}
+
+// 构建hive建表子句，每个子句单独一行
+func (o *TableOption) beautifyHiveClauses() string {
+	if !o.isHive() {
+		return consts.Empty
+	}
+	var sql = strings.Builder{}
+	var writeClause = func(key, value string) {
+		if value != consts.Empty {
+			sql.WriteString(consts.NextLine)
+			sql.WriteString(key)
+			sql.WriteString(consts.Blank)
+			sql.WriteString(value)
+		}
+	}
+	writeClause(consts.COMMENT, o.Comment)
+	if len(o.PartitionedBy) > 0 {
+		var columns []string
+		for _, column := range o.PartitionedBy {
+			columns = append(columns, column.definition())
+		}
+		writeClause("partitioned by", consts.LeftBracket+strings.Join(columns, ", ")+consts.RightBracket)
+	}
+	writeClause("clustered by", o.ClusteredBy)
+	writeClause("row format", o.RowFormat)
+	writeClause("stored as", o.StoredAs)
+	writeClause("location", o.Location)
+	writeClause("tblproperties", o.Properties)
+	return sql.String()
+}
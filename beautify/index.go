@@ -0,0 +1,174 @@
+package beautify
+
+import (
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+	"github.com/go-xuan/sqlx/utils"
+)
+
+// ParseCreateIndexSQL 解析创建索引SQL
+func ParseCreateIndexSQL(sql string, indent ...int) *CreateIndex {
+	return parseCreateIndex(sql, nil, indent...)
+}
+
+// 按方言解析创建索引SQL，未指定方言时为nil
+func parseCreateIndex(sql string, dialect Dialect, indent ...int) *CreateIndex {
+	// sql初始化
+	var parser = &CreateIndex{
+		Base: NewBase(sql, indent...),
+	}
+	parser.dialect = dialect
+
+	// sql解析
+	parser.parsePrepare() // 解析准备
+	parser.parseIndex()   // 解析索引名
+	parser.parseTable()   // 解析索引表及字段
+	parser.parseWhere()   // 解析where（部分索引）
+	parser.parseFinish()  // 解析完成
+
+	return parser
+}
+
+type CreateIndex struct {
+	Base
+	Type         string       // 索引类型（index、unique、fulltext、spatial）
+	Name         string       // 索引名
+	Concurrently bool         // 是否concurrently（postgresql）
+	IfNotExists  bool         // 是否if not exists
+	Only         bool         // 是否only（postgresql不作用于子表）
+	Table        *Table       // 索引表
+	Using        string       // 索引方法（btree、hash、gin）
+	Columns      []string     // 索引字段
+	Where        []*Condition // 部分索引条件
+	Extra        string       // 其他索引选项原文（include、with、tablespace等）
+}
+
+// Beautify SQL美化输出
+func (x *CreateIndex) Beautify() string {
+	var sql = strings.Builder{}
+	sql.WriteString(consts.CREATE)
+	sql.WriteString(consts.Blank)
+	if x.Type != consts.INDEX {
+		sql.WriteString(x.Type)
+		sql.WriteString(consts.Blank)
+	}
+	sql.WriteString(consts.INDEX)
+	if x.Concurrently {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.CONCURRENTLY)
+	}
+	if x.IfNotExists {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.IFNOTEXISTS)
+	}
+	if x.Name != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(x.Name)
+	}
+	sql.WriteString(consts.NextLine)
+	sql.WriteString(x.align(consts.ON))
+	sql.WriteString(consts.Blank)
+	if x.Only {
+		sql.WriteString("only ")
+	}
+	sql.WriteString(x.Table.beautify())
+	if x.Using != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.USING)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(x.Using)
+	}
+	sql.WriteString(" (")
+	sql.WriteString(strings.Join(x.Columns, ", "))
+	sql.WriteString(consts.RightBracket)
+	if x.Extra != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(x.Extra)
+	}
+	if conditions := x.Where; len(conditions) > 0 {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(x.align(consts.WHERE))
+		sql.WriteString(consts.Blank)
+		for i, condition := range conditions {
+			if i > 0 {
+				sql.WriteString(consts.NextLine)
+			}
+			sql.WriteString(condition.beautify(x.indent))
+		}
+	}
+	return x.finish(sql.String())
+}
+
+// 提取索引名
+func (x *CreateIndex) parseIndex() *CreateIndex {
+	sql := x.tempSql
+	var header, rest = sql, consts.Empty
+	if index := utils.IndexOfKeywordFirst(strings.ToLower(sql), consts.ON); index >= 0 {
+		header, rest = sql[:index], sql[index+3:]
+	}
+	x.Type = consts.INDEX
+	for _, word := range strings.Fields(header)[1:] {
+		switch lower := strings.ToLower(word); lower {
+		case consts.UNIQUE, consts.FULLTEXT, consts.SPATIAL:
+			x.Type = lower
+		case consts.INDEX:
+		case consts.CONCURRENTLY:
+			x.Concurrently = true
+		case consts.IF, consts.NOT:
+		case consts.EXISTS:
+			x.IfNotExists = true
+		default:
+			x.Name = word
+		}
+	}
+	x.tempSql = strings.TrimSpace(rest)
+	return x
+}
+
+// 提取索引表及字段
+func (x *CreateIndex) parseTable() *CreateIndex {
+	sql := x.tempSql
+	var where string
+	if index := utils.IndexExcludeBrackets(strings.ToLower(sql), consts.WHERE, true); index >= 0 {
+		sql, where = sql[:index], sql[index:]
+	}
+	var extras []string
+	var words = utils.SplitFieldsExcludeInBracket(sql)
+	if len(words) > 1 && strings.EqualFold(words[0], "only") { // postgresql：on only t
+		x.Only, words = true, words[1:]
+	}
+	for i, word := range words {
+		if name, columns := splitNameColumns(word); i == 0 {
+			x.Table = NewTable(name)
+			if columns != consts.Empty {
+				x.Columns = splitColumns(columns)
+			}
+		} else if x.Columns == nil && columns != consts.Empty {
+			if name != consts.Empty { // postgresql：using gin(cols)
+				x.Using = name
+			}
+			x.Columns = splitColumns(columns)
+		} else if x.Columns == nil && strings.EqualFold(word, consts.USING) {
+			continue
+		} else if x.Columns == nil {
+			x.Using = word
+		} else {
+			extras = append(extras, word)
+		}
+	}
+	if x.Table == nil {
+		panic("当前输入sql无法解析 " + x.originSql)
+	}
+	x.Extra = x.restore(strings.Join(extras, consts.Blank))
+	x.tempSql = where
+	return x
+}
+
+// 提取部分索引条件
+func (x *CreateIndex) parseWhere() *CreateIndex {
+	if sql := x.tempSql; sql != "" {
+		x.Where, x.tempSql = extractWhere(sql, x.dialect, x.indent)
+	}
+	return x
+}
@@ -2,6 +2,7 @@ package beautify
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/go-xuan/sqlx/consts"
@@ -10,17 +11,25 @@ import (
 
 // ParseInsertSQL 解析插入SQL
 func ParseInsertSQL(sql string, indent ...int) *Insert {
+	return parseInsert(sql, nil, indent...)
+}
+
+// 按方言解析插入SQL，未指定方言时为nil
+func parseInsert(sql string, dialect Dialect, indent ...int) *Insert {
 	// sql初始化
 	var parser = &Insert{
 		Base: NewBase(sql, indent...),
 	}
+	parser.dialect = dialect
 
 	// sql解析
-	parser.parsePrepare()  // 解析准备
-	parser.parseTable()    // 解析主表
-	parser.extractFields() // 解析字段
-	parser.extractValues() // 解析插入值
-	parser.parseFinish()   // 解析完成
+	parser.parsePrepare()   // 解析准备
+	parser.parseReturning() // 解析returning
+	parser.parseUpsert()    // 解析冲突更新
+	parser.parseTable()     // 解析主表
+	parser.extractFields()  // 解析字段
+	parser.extractValues()  // 解析插入值
+	parser.parseFinish()    // 解析完成
 
 	return parser
 }
@@ -31,96 +40,242 @@ type Insert struct {
 	Fields    []*Field   // 插入字段
 	ValueData [][]string // 插入值
 	Query     *Select    // 子查询
+	Output    *Output    // sql server：output inserted.*
+	Returning *Output    // oracle/postgresql：returning id into :id
+	Overwrite bool       // hive：insert overwrite table
+	Partition []string   // hive：写入分区（partition (dt='2024-01-01', hr)）
+	Ignore    bool       // mysql：insert ignore
+	Upsert    *Upsert    // 冲突更新（mysql：on duplicate key update，postgresql/sqlite：on conflict）
+}
+
+// 冲突更新子句关键字
+const (
+	onDuplicateKey = "on duplicate key update"
+	onConflict     = "on conflict"
+)
+
+var upsertPattern = regexp.MustCompile(`(?i)\son\s+(duplicate\s+key\s+update|conflict)(\s|\(|$)`)
+
+// Upsert 冲突更新：mysql on duplicate key update a = values(a)，postgresql/sqlite on conflict (id) do update set a = excluded.a
+type Upsert struct {
+	Keyword  string   // 子句关键字（on duplicate key update、on conflict）
+	Conflict []string // 冲突字段（on conflict (id)）
+	Nothing  bool     // 冲突时不做处理（on conflict do nothing）
+	Fields   []*Field // 更新字段，Value为更新值
+	Where    string   // 更新条件（on conflict (id) do update set ... where ...）
 }
 
 func (x *Insert) Beautify() string {
 	var sql = strings.Builder{}
 	sql.WriteString(x.beautifyInsert())
 	sql.WriteString(x.beautifyFields())
+	if x.Output != nil {
+		sql.WriteString(x.Output.beautify())
+		sql.WriteString(consts.NextLine)
+	}
 	sql.WriteString(x.beautifyValues())
-	if replacer := x.replacer; replacer != nil {
-		return replacer.Replace(sql.String())
-	} else {
-		return sql.String()
+	sql.WriteString(x.beautifyUpsert())
+	if x.Returning != nil {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(x.Returning.beautify())
 	}
+	return x.finish(sql.String())
 }
 
 // 构建查询字段sql
 func (x *Insert) beautifyInsert() string {
 	var sql = strings.Builder{}
-	sql.WriteString("insert into ")
+	if x.Overwrite {
+		sql.WriteString("insert overwrite table ")
+	} else if x.Ignore {
+		sql.WriteString("insert ignore into ")
+	} else if len(x.Partition) > 0 {
+		sql.WriteString("insert into table ")
+	} else {
+		sql.WriteString("insert into ")
+	}
 	sql.WriteString(x.Table.beautify())
+	if len(x.Partition) > 0 {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.PARTITION)
+		sql.WriteString(" (")
+		sql.WriteString(strings.Join(x.Partition, ", "))
+		sql.WriteString(consts.RightBracket)
+	}
 	sql.WriteString(consts.NextLine)
 	return sql.String()
 }
 
 // 构建查询字段sql
 func (x *Insert) beautifyFields() string {
+	if len(x.Fields) == 0 {
+		return consts.Empty
+	}
+	return Align(x.indent-1) + beautifyColumnList(x.Fields, x.format(), x.indent) + consts.NextLine
+}
+
+// 构建查询字段sql
+func (x *Insert) beautifyValues() string {
+	var sql = strings.Builder{}
+	if x.Query != nil {
+		sql.WriteString(x.Query.Beautify())
+	} else if x.ValueData != nil {
+		sql.WriteString(consts.VALUES)
+		sql.WriteString(consts.NextLine)
+		for i, values := range x.ValueData {
+			if i > 0 {
+				sql.WriteString(consts.Comma)
+				sql.WriteString(consts.NextLine)
+			}
+			sql.WriteString(Align(x.indent - 1))
+			sql.WriteString(beautifyValueList(values, len(x.Fields) >= 10, x.indent))
+		}
+	}
+	return sql.String()
+}
+
+// 构建插入字段列表（insert语句以及merge语句的insert分支），字段总宽度或数量超出格式化选项时逐行对齐，indent为续行缩进
+func beautifyColumnList(fields []*Field, format *FormatOptions, indent int) string {
 	var sql = strings.Builder{}
 	var maxLen int
-	for _, field := range x.Fields {
-		maxLen += len(field.Name)
+	for _, field := range fields {
+		maxLen += utils.DisplayWidth(field.column())
 	}
 
 	var nextLine bool
-	if maxLen > 120 || len(x.Fields) > 10 {
+	if maxLen > format.InsertWrapWidth || len(fields) > format.InsertWrapCount {
 		nextLine = true
 	}
-	sql.WriteString(x.align(consts.LeftBracket))
-	for i, field := range x.Fields {
+	sql.WriteString(consts.LeftBracket)
+	for i, field := range fields {
 		if i > 0 {
 			sql.WriteString(consts.Comma)
 			if nextLine {
 				sql.WriteString(consts.NextLine)
-				sql.WriteString(x.align())
+				sql.WriteString(Align(indent))
 			} else {
 				sql.WriteString(consts.Blank)
 			}
 		}
-		sql.WriteString(field.Name)
+		sql.WriteString(field.column())
 	}
 	sql.WriteString(consts.RightBracket)
-	sql.WriteString(consts.NextLine)
 	return sql.String()
 }
 
-// 构建查询字段sql
-func (x *Insert) beautifyValues() string {
+// 构建一组插入值（insert语句以及merge语句的insert分支），nextLine表示逐行对齐，indent为续行缩进
+func beautifyValueList(values []string, nextLine bool, indent int) string {
 	var sql = strings.Builder{}
-	if x.Query != nil {
-		sql.WriteString(x.Query.Beautify())
-	} else if x.ValueData != nil {
-		var nextLine bool
-		if len(x.Fields) >= 10 {
-			nextLine = true
-		}
-		sql.WriteString(consts.VALUES)
-		sql.WriteString(consts.NextLine)
-		for i, values := range x.ValueData {
-			if i > 0 {
-				sql.WriteString(consts.Comma)
+	sql.WriteString(consts.LeftBracket)
+	for j, value := range values {
+		if j > 0 {
+			sql.WriteString(consts.Comma)
+			if nextLine {
 				sql.WriteString(consts.NextLine)
+				sql.WriteString(Align(indent))
+			} else {
+				sql.WriteString(consts.Blank)
 			}
-			sql.WriteString(x.align(consts.LeftBracket))
-			for j, value := range values {
-				if j > 0 {
-					sql.WriteString(consts.Comma)
-					if nextLine {
-						sql.WriteString(consts.NextLine)
-						sql.WriteString(x.align())
-					} else {
-						sql.WriteString(consts.Blank)
-					}
+		}
+		sql.WriteString(value)
+	}
+	if nextLine {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(Align(indent, consts.RightBracket))
+	} else {
+		sql.WriteString(consts.RightBracket)
+	}
+	return sql.String()
+}
+
+// 提取冲突更新（on duplicate key update a = values(a)、on conflict (id) do update set a = excluded.a、on conflict do nothing）
+func (x *Insert) parseUpsert() *Insert {
+	sql := x.tempSql
+	for _, match := range upsertPattern.FindAllStringSubmatchIndex(sql, -1) {
+		prefix := sql[:match[0]]
+		if strings.Count(prefix, consts.LeftBracket) != strings.Count(prefix, consts.RightBracket) {
+			continue
+		}
+		var upsert = &Upsert{Keyword: onDuplicateKey}
+		rest := strings.TrimSpace(sql[match[3]:])
+		if strings.EqualFold(sql[match[2]:match[3]], "conflict") {
+			upsert.Keyword = onConflict
+			if from, to := utils.BetweenOfString(rest, consts.LeftBracket, consts.RightBracket); from == 0 && to > 0 {
+				list, last := utils.SplitExcludeInBracket(rest[1:to], consts.Comma)
+				for _, column := range append(list, last) {
+					upsert.Conflict = append(upsert.Conflict, strings.TrimSpace(column))
 				}
-				sql.WriteString(value)
+				rest = strings.TrimSpace(rest[to+1:])
 			}
-			if nextLine {
-				sql.WriteString(consts.NextLine)
-				sql.WriteString(x.align(consts.RightBracket))
+			lower := strings.ToLower(rest)
+			if upsert.Nothing = strings.HasPrefix(lower, "do nothing"); upsert.Nothing {
+				rest = consts.Empty
+			} else if strings.HasPrefix(lower, "do update set ") {
+				rest = rest[14:]
 			} else {
-				sql.WriteString(consts.RightBracket)
+				panic("当前输入sql无法解析 " + x.originSql)
+			}
+		}
+		if index := utils.IndexExcludeBrackets(strings.ToLower(rest), consts.WHERE, true); index > 0 {
+			upsert.Where, rest = strings.TrimSpace(rest[index+6:]), rest[:index]
+		}
+		if rest = strings.TrimSuffix(strings.TrimSpace(rest), consts.Semicolon); rest != consts.Empty {
+			list, last := utils.SplitExcludeInBracket(rest, consts.Comma)
+			for _, item := range append(list, last) {
+				name, value := utils.CutString(item, consts.EQ)
+				upsert.Fields = append(upsert.Fields, &Field{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)})
 			}
 		}
+		x.Upsert, x.tempSql = upsert, prefix
+		break
+	}
+	return x
+}
+
+// 构建冲突更新sql
+func (x *Insert) beautifyUpsert() string {
+	var upsert = x.Upsert
+	if upsert == nil {
+		return consts.Empty
+	}
+	var sql = strings.Builder{}
+	sql.WriteString(consts.NextLine)
+	sql.WriteString(upsert.Keyword)
+	if len(upsert.Conflict) > 0 {
+		sql.WriteString(" (")
+		sql.WriteString(strings.Join(upsert.Conflict, ", "))
+		sql.WriteString(consts.RightBracket)
+	}
+	if upsert.Keyword == onConflict {
+		if upsert.Nothing {
+			sql.WriteString(" do nothing")
+			return sql.String()
+		}
+		sql.WriteString(" do update set")
+	}
+	var maxLen int
+	for _, field := range upsert.Fields {
+		if l := utils.DisplayWidth(field.Name); maxLen < l {
+			maxLen = l
+		}
+	}
+	for i, field := range upsert.Fields {
+		if i > 0 {
+			sql.WriteString(consts.Comma)
+		}
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(x.align())
+		sql.WriteString(field.Name)
+		sql.WriteString(strings.Repeat(consts.Blank, maxLen-utils.DisplayWidth(field.Name)+1))
+		sql.WriteString(consts.EQ)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(field.Value)
+	}
+	if upsert.Where != consts.Empty {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(consts.WHERE)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(upsert.Where)
 	}
 	return sql.String()
 }
@@ -131,31 +286,57 @@ func (x *Insert) parseTable() *Insert {
 	if index := utils.IndexOfKeywordFirst(sql, consts.INSERT); index == 0 {
 		sql = sql[7:]
 	}
-	// 去除into关键字
+	// mysql：insert ignore into
+	if strings.HasPrefix(strings.ToLower(sql), "ignore ") {
+		x.Ignore, sql = true, sql[7:]
+	}
+	// 去除into关键字，hive：insert overwrite table
 	if index := utils.IndexOfKeywordFirst(sql, consts.INTO); index == 0 {
 		sql = sql[5:]
+	} else if strings.HasPrefix(strings.ToLower(sql), "overwrite ") {
+		x.Overwrite, sql = true, sql[10:]
+	}
+	if strings.HasPrefix(strings.ToLower(sql), consts.TABLE+consts.Blank) {
+		sql = sql[6:]
+	}
+	// 表名之后为字段括号、分区或者写入值
+	sql = strings.TrimSpace(sql)
+	var end = len(sql)
+	if index := utils.IndexExcludeQuotes(sql, consts.Blank, 0); index >= 0 {
+		end = index
 	}
-	// 根据set关键字进行拆分
-	if index := utils.IndexOfString(sql, consts.LeftBracket); index >= 0 {
-		x.Table = &Table{
-			Name: strings.TrimSpace(sql[:index-1]),
+	if index := utils.IndexExcludeQuotes(sql[:end], consts.LeftBracket, 0); index >= 0 {
+		end = index
+	}
+	x.Table, sql = NewTable(sql[:end]), strings.TrimSpace(sql[end:])
+	// hive：partition (dt='2024-01-01', hr)
+	if strings.HasPrefix(strings.ToLower(sql), consts.PARTITION) {
+		if from, to := utils.BetweenOfString(sql, consts.LeftBracket, consts.RightBracket); from > 0 && to > from {
+			list, last := utils.SplitExcludeInBracket(sql[from+1:to], consts.Comma)
+			for _, value := range append(list, last) {
+				x.Partition = append(x.Partition, strings.TrimSpace(value))
+			}
+			sql = strings.TrimSpace(sql[to+1:])
 		}
-		x.tempSql = sql[index:]
 	}
+	x.tempSql = sql
 	return x
 }
 
 func (x *Insert) extractFields() *Insert {
 	sql := x.tempSql
-	// 根据set关键字进行拆分
+	// 未指定字段（insert into t values (...)、insert overwrite table t select ...）
+	if !strings.HasPrefix(sql, consts.LeftBracket) || utils.IndexOfKeywordFirst(sql[1:], consts.SELECT) == 0 {
+		return x
+	}
 	if from, to := utils.BetweenOfString(sql, consts.LeftBracket, consts.RightBracket); from >= 0 && from < to {
-		x.tempSql = sql[to+2:]
+		x.tempSql = strings.TrimSpace(sql[to+1:])
 		sql = sql[from+1 : to]
 	}
 	if names := strings.Split(sql, consts.Comma); len(names) > 0 {
 		var fields []*Field
 		for _, name := range names {
-			fields = append(fields, &Field{Name: name})
+			fields = append(fields, NewColumn(strings.TrimSpace(name)))
 		}
 		x.Fields = fields
 	}
@@ -164,8 +345,13 @@ func (x *Insert) extractFields() *Insert {
 
 func (x *Insert) extractValues() *Insert {
 	sql := strings.TrimLeft(x.tempSql, consts.Blank)
+	if strings.HasPrefix(strings.ToLower(sql), consts.OUTPUT+consts.Blank) && x.supports(OutputClause) {
+		if _, index := utils.ContainsKeywords(sql, consts.VALUES, consts.VALUE, consts.SELECT); index > 0 {
+			x.Output, sql = NewOutput(consts.OUTPUT, sql[7:index]), sql[index:]
+		}
+	}
 	if index := utils.IndexOfKeywordFirst(sql, consts.SELECT); index == 0 {
-		if query := ParseSelectSQL(sql); query != nil && len(query.Fields) == len(x.Fields) {
+		if query := parseSelect(sql, x.dialect); query != nil && (len(x.Fields) == 0 || len(query.Fields) == len(x.Fields)) {
 			x.Query = query
 		} else {
 			panic("select字段数量和insert字段数量不匹配")
@@ -191,7 +377,7 @@ func (x *Insert) extractValues() *Insert {
 
 	valuesList = append(valuesList, lastValues)
 	for _, valuesSql := range valuesList {
-		if values := utils.SplitValuesSql(valuesSql); len(values) == len(x.Fields) {
+		if values := utils.SplitValuesSql(valuesSql); len(x.Fields) == 0 || len(values) == len(x.Fields) {
 			x.ValueData = append(x.ValueData, values)
 		} else {
 			var names []string
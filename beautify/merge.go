@@ -0,0 +1,317 @@
+package beautify
+
+import (
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+	"github.com/go-xuan/sqlx/utils"
+)
+
+// ParseMergeSQL 解析合并SQL
+func ParseMergeSQL(sql string, indent ...int) *Merge {
+	return parseMerge(sql, nil, indent...)
+}
+
+// 按方言解析合并SQL，未指定方言时为nil
+func parseMerge(sql string, dialect Dialect, indent ...int) *Merge {
+	// sql初始化
+	var parser = &Merge{
+		Base: NewBase(sql, indent...),
+	}
+	parser.dialect = dialect
+
+	// sql解析
+	parser.parsePrepare()  // 解析准备
+	parser.parseTarget()   // 解析目标表
+	parser.parseSource()   // 解析源表
+	parser.parseOn()       // 解析关联条件
+	parser.parseBranches() // 解析when分支
+	parser.parseFinish()   // 解析完成
+
+	return parser
+}
+
+type Merge struct {
+	Base
+	Target   *Table         // 目标表
+	Source   *Table         // 源表或子查询
+	On       []*Condition   // 关联条件
+	Branches []*MergeBranch // when分支
+}
+
+// MergeBranch merge语句when分支解析
+type MergeBranch struct {
+	Matched   bool         // when matched / when not matched
+	By        string       // 匹配对象（sql server：by target、by source）
+	Condition []*Condition // 分支附加条件（when matched and ...）
+	Action    string       // 分支动作（update、delete、insert、do nothing）
+	Fields    []*Field     // 更新字段（update set）或插入字段（insert）
+	ValueData [][]string   // 插入值，同insert语句
+	Row       string       // 非values形式的插入内容（default values、row），原样输出
+	Where     []*Condition // 动作条件（oracle：update set ... where ...）
+	Delete    []*Condition // 更新后删除条件（oracle：update set ... delete where ...）
+}
+
+// Beautify SQL美化输出
+func (x *Merge) Beautify() string {
+	var sql = strings.Builder{}
+	sql.WriteString(Align(x.format().Indent, consts.MERGE))
+	sql.WriteString(consts.Blank)
+	sql.WriteString(consts.INTO)
+	sql.WriteString(consts.Blank)
+	sql.WriteString(x.Target.beautify(x.format().aliasAs(false)))
+	sql.WriteString(consts.NextLine)
+	sql.WriteString(x.align(consts.USING))
+	sql.WriteString(consts.Blank)
+	sql.WriteString(x.Source.beautify(x.format().aliasAs(false)))
+	sql.WriteString(consts.NextLine)
+	sql.WriteString(x.align(consts.ON))
+	sql.WriteString(consts.Blank)
+	sql.WriteString(consts.LeftBracket)
+	for i, condition := range x.On {
+		if i > 0 {
+			sql.WriteString(consts.NextLine)
+		}
+		sql.WriteString(condition.beautify(x.indent))
+	}
+	sql.WriteString(consts.RightBracket)
+	for _, branch := range x.Branches {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(branch.beautify(x.indent, x.format()))
+	}
+	return x.finish(sql.String())
+}
+
+// 提取目标表
+func (x *Merge) parseTarget() *Merge {
+	sql := x.tempSql
+	// 去除merge into关键字
+	if words := strings.SplitN(sql, consts.Blank, 3); len(words) == 3 && strings.EqualFold(words[1], consts.INTO) {
+		sql = words[2]
+	} else if len(words) > 1 {
+		sql = strings.Join(words[1:], consts.Blank)
+	}
+	index := utils.IndexExcludeBrackets(strings.ToLower(sql), consts.USING, true)
+	if index < 0 {
+		panic("当前输入sql无法解析 " + x.originSql)
+	}
+	x.Target, _ = extractTable(sql[:index-1], x.indent, x.dialect)
+	x.tempSql = sql[index+6:]
+	return x
+}
+
+// 提取源表
+func (x *Merge) parseSource() *Merge {
+	sql := x.tempSql
+	index := utils.IndexExcludeBrackets(strings.ToLower(sql), consts.ON, true)
+	if index < 0 {
+		panic("当前输入sql无法解析 " + x.originSql)
+	}
+	x.Source, _ = extractTable(sql[:index-1], x.indent, x.dialect)
+	x.tempSql = sql[index+3:]
+	return x
+}
+
+// 提取关联条件
+func (x *Merge) parseOn() *Merge {
+	sql := x.tempSql
+	indices := mergeBranchIndices(sql)
+	if len(indices) == 0 {
+		panic("当前输入sql无法解析 " + x.originSql)
+	}
+	x.On = newConditions(strings.TrimSpace(sql[:indices[0]]), x.dialect, x.indent)
+	x.tempSql = sql[indices[0]:]
+	return x
+}
+
+// 提取when分支
+func (x *Merge) parseBranches() *Merge {
+	sql := strings.TrimSuffix(strings.TrimSpace(x.tempSql), consts.Semicolon)
+	indices := append(mergeBranchIndices(sql), len(sql))
+	for i := 0; i < len(indices)-1; i++ {
+		x.Branches = append(x.Branches, x.newBranch(strings.TrimSpace(sql[indices[i]:indices[i+1]])))
+	}
+	return x
+}
+
+// 解析单个when分支
+func (x *Merge) newBranch(sql string) *MergeBranch {
+	var branch = &MergeBranch{Matched: true}
+	lower := strings.ToLower(sql)
+	index := utils.IndexExcludeBrackets(lower, consts.THEN, true)
+	if index < 0 {
+		panic("当前输入sql无法解析 " + x.originSql)
+	}
+	// when [not] matched [by target|source] [and ...]
+	header, action := sql[5:index-1], strings.TrimSpace(sql[index+5:])
+	if i := utils.IndexOfKeywordFirst(strings.ToLower(header), consts.AND); i >= 0 {
+		branch.Condition = newConditions(header[i+4:], x.dialect, x.indent)
+		header = header[:i]
+	}
+	for _, word := range strings.Fields(strings.ToLower(header)) {
+		switch word {
+		case consts.NOT:
+			branch.Matched = false
+		case consts.TARGET, consts.SOURCE:
+			branch.By = consts.BY + consts.Blank + word
+		}
+	}
+	// 分支动作
+	words := strings.Fields(action)
+	branch.Action = strings.ToLower(words[0])
+	switch branch.Action {
+	case consts.UPDATE:
+		action = strings.TrimSpace(action[6:])
+		if i := utils.IndexOfKeywordFirst(strings.ToLower(action), consts.SET); i == 0 {
+			action = action[4:]
+		}
+		if i := utils.IndexExcludeBrackets(strings.ToLower(action), consts.DELETE+consts.Blank+consts.WHERE, true); i >= 0 {
+			branch.Delete, action = newConditions(action[i+13:], x.dialect, x.indent), action[:i]
+		}
+		if i := utils.IndexExcludeBrackets(strings.ToLower(action), consts.WHERE, true); i >= 0 {
+			branch.Where, action = newConditions(action[i+6:], x.dialect, x.indent), action[:i]
+		}
+		list, last := utils.SplitExcludeInBracket(action, consts.Comma)
+		for _, field := range append(list, last) {
+			name, value := utils.CutString(field, consts.EQ)
+			branch.Fields = append(branch.Fields, &Field{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)})
+		}
+	case consts.INSERT:
+		action = strings.TrimSpace(action[6:])
+		if from, to := utils.BetweenOfString(action, consts.LeftBracket, consts.RightBracket); from == 0 {
+			for _, name := range splitColumns(action[from : to+1]) {
+				branch.Fields = append(branch.Fields, &Field{Name: name})
+			}
+			action = strings.TrimSpace(action[to+1:])
+		}
+		if i := utils.IndexOfKeywordFirst(strings.ToLower(action), consts.VALUES); i == 0 {
+			action = strings.TrimSpace(action[6:])
+			if i = utils.IndexExcludeBrackets(strings.ToLower(action), consts.WHERE, true); i >= 0 {
+				branch.Where, action = newConditions(action[i+6:], x.dialect, x.indent), action[:i]
+			}
+			branch.ValueData = [][]string{utils.SplitValuesSql(action)}
+		} else { // insert default values / insert row
+			branch.Row = action
+		}
+	case consts.DELETE:
+		if i := utils.IndexExcludeBrackets(strings.ToLower(action), consts.WHERE, true); i >= 0 {
+			branch.Where = newConditions(action[i+6:], x.dialect, x.indent)
+		}
+	default: // do nothing
+		branch.Action = strings.ToLower(action)
+	}
+	return branch
+}
+
+func (b *MergeBranch) beautify(indent int, format *FormatOptions) string {
+	var sql = strings.Builder{}
+	sql.WriteString(Align(indent, consts.WHEN))
+	if !b.Matched {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.NOT)
+	}
+	sql.WriteString(consts.Blank)
+	sql.WriteString(consts.MATCHED)
+	if b.By != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(b.By)
+	}
+	for i, condition := range b.Condition {
+		sql.WriteString(consts.Blank)
+		if i == 0 {
+			sql.WriteString(consts.AND)
+			sql.WriteString(consts.Blank)
+		}
+		sql.WriteString(condition.beautify(0))
+	}
+	sql.WriteString(consts.Blank)
+	sql.WriteString(consts.THEN)
+	sql.WriteString(consts.NextLine)
+	indent++
+	sql.WriteString(Align(indent))
+	switch b.Action {
+	case consts.UPDATE:
+		sql.WriteString("update set ")
+		var maxLen int
+		for _, field := range b.Fields {
+			if l := utils.DisplayWidth(field.Name); maxLen < l {
+				maxLen = l
+			}
+		}
+		for i, field := range b.Fields {
+			if i > 0 {
+				sql.WriteString(consts.Comma)
+				sql.WriteString(consts.NextLine)
+				sql.WriteString(Align(indent + 11))
+			}
+			sql.WriteString(field.Name)
+			sql.WriteString(strings.Repeat(consts.Blank, maxLen-utils.DisplayWidth(field.Name)+1))
+			sql.WriteString(consts.EQ)
+			sql.WriteString(consts.Blank)
+			sql.WriteString(field.Value)
+		}
+	case consts.INSERT:
+		sql.WriteString(consts.INSERT)
+		sql.WriteString(consts.Blank)
+		if len(b.Fields) > 0 { // 字段以及插入值与insert语句的换行、对齐规则相同，续行对齐左括号之后
+			sql.WriteString(beautifyColumnList(b.Fields, format, indent+8))
+			sql.WriteString(consts.NextLine)
+			sql.WriteString(Align(indent))
+		}
+		if b.Row != consts.Empty {
+			sql.WriteString(b.Row)
+		}
+		for _, values := range b.ValueData {
+			sql.WriteString(consts.VALUES)
+			sql.WriteString(consts.Blank)
+			sql.WriteString(beautifyValueList(values, len(b.Fields) >= 10, indent+8))
+		}
+	default:
+		sql.WriteString(b.Action)
+	}
+	for i, condition := range b.Where {
+		sql.WriteString(consts.NextLine)
+		if i == 0 {
+			sql.WriteString(Align(indent))
+			sql.WriteString(consts.WHERE)
+			sql.WriteString(consts.Blank)
+			sql.WriteString(condition.beautify(0))
+		} else {
+			sql.WriteString(condition.beautify(indent + 5))
+		}
+	}
+	for i, condition := range b.Delete {
+		sql.WriteString(consts.NextLine)
+		if i == 0 {
+			sql.WriteString(Align(indent))
+			sql.WriteString(consts.DELETE)
+			sql.WriteString(consts.Blank)
+			sql.WriteString(consts.WHERE)
+			sql.WriteString(consts.Blank)
+			sql.WriteString(condition.beautify(0))
+		} else {
+			sql.WriteString(condition.beautify(indent + 12))
+		}
+	}
+	return sql.String()
+}
+
+// 获取所有when分支的起始下标（when matched、when not matched），排除括号内以及case when
+func mergeBranchIndices(sql string) []int {
+	var indices []int
+	lower := strings.ToLower(sql)
+	var offset int
+	for {
+		index := utils.IndexExcludeBrackets(lower[offset:], consts.WHEN, true)
+		if index < 0 {
+			break
+		}
+		index += offset
+		rest := strings.TrimSpace(lower[index+4:])
+		if strings.HasPrefix(rest, consts.MATCHED) || strings.HasPrefix(rest, consts.NOT+consts.Blank+consts.MATCHED) {
+			indices = append(indices, index)
+		}
+		offset = index + 4
+	}
+	return indices
+}
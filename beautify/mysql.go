@@ -0,0 +1,83 @@
+package beautify
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+	"github.com/go-xuan/sqlx/utils"
+)
+
+// mysql条件运算符，按顺序匹配
+var mysqlOperators = []string{"<=>", "not regexp", "regexp", "not rlike", "rlike", "sounds like"}
+
+// straight_join按join拆分之后的残留前缀
+const straightPrefix = "straight_"
+
+// mysql索引提示：use|force|ignore index|key [for join|order by|group by] (idx, ...)
+var indexHintPattern = regexp.MustCompile(`(?i)\s+(use|force|ignore)\s+(index|key)(\s+for\s+(join|order\s+by|group\s+by))?\s*\(([^)]*)\)`)
+
+// 是否mysql查询修饰符
+func isSelectModifier(word string) bool {
+	switch word {
+	case "sql_calc_found_rows", "sql_no_cache", "sql_cache", "high_priority", consts.STRAIGHTJOIN,
+		"sql_small_result", "sql_big_result", "sql_buffer_result", "distinctrow":
+		return true
+	default:
+		return false
+	}
+}
+
+// 提取查询修饰符（sql_calc_found_rows、sql_no_cache、high_priority等），返回去除修饰符之后的字段sql
+func (x *Select) parseModifiers(sql string) string {
+	for {
+		word, rest := utils.CutString(sql, consts.Blank)
+		if rest == consts.Empty || !isSelectModifier(strings.ToLower(word)) {
+			return sql
+		}
+		x.Modifiers = append(x.Modifiers, strings.ToLower(word))
+		sql = strings.TrimSpace(rest)
+	}
+}
+
+// 提取mysql索引提示并按紧邻的表名或别名归类，仅处理当前查询层级（不包含子查询），返回去除索引提示之后的sql
+func extractIndexHints(sql string, hints map[string][]string) string {
+	var builder = strings.Builder{}
+	var offset int
+	for _, match := range indexHintPattern.FindAllStringSubmatchIndex(sql, -1) {
+		if prefix := sql[:match[0]]; strings.Count(prefix, consts.LeftBracket) != strings.Count(prefix, consts.RightBracket) {
+			continue
+		}
+		var hint = strings.ToLower(sql[match[2]:match[3]] + consts.Blank + sql[match[4]:match[5]])
+		if match[6] >= 0 {
+			hint += consts.Blank + strings.ToLower(strings.Join(strings.Fields(sql[match[6]:match[7]]), consts.Blank))
+		}
+		hint += consts.Blank + joinHintArgs(sql[match[10]:match[11]])
+		addTableHint(hints, sql[offset:match[0]], hint)
+		builder.WriteString(sql[offset:match[0]])
+		offset = match[1]
+	}
+	builder.WriteString(sql[offset:])
+	return builder.String()
+}
+
+// 判断字段末尾的单词是否为省略as的别名，排除运算符右侧的操作数（b div 2、c mod 3、now() - interval 1 day）
+func isFieldAlias(expr, alias string) bool {
+	if !aliasPattern.MatchString(alias) || strings.EqualFold(alias, "end") {
+		return false
+	}
+	words := strings.Fields(expr)
+	switch last := strings.ToLower(words[len(words)-1]); last {
+	case "div", "mod", "and", "or", "not", "xor", "is", "like", "interval", "collate", "case", "when", "then", "else":
+		return false
+	default:
+		if strings.Trim(last[len(last)-1:], "+-*/%=<>|&^~!") == consts.Empty {
+			return false
+		}
+	}
+	// interval 1 day
+	return len(words) < 2 || !strings.EqualFold(words[len(words)-2], "interval")
+}
+
+// 别名：单个标识符（支持中文等非ASCII字符），支持引号标识符
+var aliasPattern = regexp.MustCompile("^([\\p{L}\\p{N}_]+|`[^`]+`|\"[^\"]+\"|\\[[^\\]]+\\])$")
@@ -0,0 +1,213 @@
+package beautify
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+	"github.com/go-xuan/sqlx/utils"
+)
+
+var (
+	outerJoinPattern = regexp.MustCompile(`\s*\(\s*\+\s*\)$`)  // oracle旧式外连接标记：a.id = b.id(+)
+	priorPattern     = regexp.MustCompile(`(?i)(^|\s)prior\s`) // connect by prior a = b
+	nocyclePattern   = regexp.MustCompile(`(?i)^nocycle\s+`)   // connect by nocycle prior a = b
+)
+
+// oracle旧式外连接标记
+const outerJoinMark = "(+)"
+
+// 集合运算符，按顺序匹配，union all需要排在union之前
+var setOperators = []string{"union all", "union", "intersect", "except", "minus"}
+
+// SetOperation 集合运算（union、union all、intersect、except、oracle：minus）
+type SetOperation struct {
+	Operator string  // 集合运算符
+	Select   *Select // 参与运算的查询
+}
+
+// 提取集合运算，当前查询仅保留第一个查询，后续查询逐个解析
+func (x *Select) parseSetOperations() *Select {
+	sql := x.tempSql
+	hit, index := x.firstSetOperator(sql)
+	if index < 0 || !strings.HasPrefix(sql, consts.SELECT) {
+		return x
+	}
+	x.tempSql = sql[:index]
+	for index >= 0 {
+		var operator = hit
+		sql = sql[index+len(hit):]
+		part := sql
+		if hit, index = x.firstSetOperator(sql); index >= 0 {
+			part = sql[:index]
+		}
+		x.SetOperations = append(x.SetOperations, &SetOperation{
+			Operator: operator,
+			Select:   parseSelect(setOperand(part), x.dialect, x.indent-6),
+		})
+	}
+	return x
+}
+
+// 去除集合运算查询两端的括号，括号之后的排序、限数等子句保留在查询末尾（(select a from t) order by a）
+func setOperand(sql string) string {
+	sql = strings.TrimSpace(sql)
+	if from, to := utils.BetweenOfString(sql, consts.LeftBracket, consts.RightBracket); from == 0 && to > 0 {
+		return strings.TrimSpace(utils.TrimBrackets(sql[:to+1]) + sql[to+1:])
+	}
+	return sql
+}
+
+// 获取当前查询层级中首个集合运算符及其下标，minus仅在方言支持时识别
+func (x *Select) firstSetOperator(sql string) (string, int) {
+	lower := strings.ToLower(sql)
+	var hit, index = consts.Empty, -1
+	for _, operator := range setOperators {
+		if operator == "minus" && !x.supports(SetMinusClause) {
+			continue
+		} else if i := utils.IndexExcludeBrackets(lower, consts.Blank+operator+consts.Blank, false); i >= 0 && (index < 0 || i+1 < index) {
+			hit, index = operator, i+1
+		}
+	}
+	return hit, index
+}
+
+func (x *Select) beautifySetOperations() string {
+	sql := strings.Builder{}
+	for _, operation := range x.SetOperations {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(Align(x.indent - defaultIndent))          // 与当前查询的select同列起始，子查询中按其缩进量补齐
+		sql.WriteString(Align(defaultIndent, operation.Operator)) // 按首个单词对齐（ union all），与是否嵌套无关
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(Align(x.indent - defaultIndent))
+		sql.WriteString(strings.TrimLeft(operation.Select.Beautify(), consts.Blank))
+	}
+	return sql.String()
+}
+
+// 提取层级查询（start with ... connect by [nocycle] prior a = b），仅处理当前查询层级
+func (x *Select) parseHierarchy() *Select {
+	if !x.supports(ConnectByClause) {
+		return x
+	}
+	sql := x.tempSql
+	lower := strings.ToLower(sql)
+	start := utils.IndexExcludeBrackets(lower, "start with", true)
+	connect := utils.IndexExcludeBrackets(lower, "connect by", true)
+	if connect < 0 {
+		return x
+	}
+	var begin, end = connect, len(sql)
+	if start >= 0 && start < connect {
+		begin = start
+	}
+	if _, index := utils.ContainsKeywords(sql[begin:], consts.GROUPBY, consts.HAVING); index >= 0 {
+		end = begin + index
+	}
+	var startSql, connectSql string
+	if start < 0 {
+		connectSql = sql[connect+10 : end]
+	} else if start < connect {
+		startSql, connectSql = sql[start+10:connect], sql[connect+10:end]
+	} else {
+		connectSql, startSql = sql[connect+10:start], sql[start+10:end]
+	}
+	if startSql = strings.TrimSpace(startSql); startSql != consts.Empty {
+		x.StartWith = newConditions(startSql, x.dialect, x.indent)
+	}
+	connectSql = strings.TrimSpace(connectSql)
+	if match := nocyclePattern.FindString(connectSql); match != consts.Empty {
+		x.NoCycle, connectSql = true, connectSql[len(match):]
+	}
+	connectSql = priorPattern.ReplaceAllStringFunc(connectSql, strings.ToLower)
+	x.ConnectBy = newConditions(connectSql, x.dialect, x.indent)
+	x.tempSql = sql[:begin] + sql[end:]
+	return x
+}
+
+// 提取order siblings by，统一为order by之后再解析排序字段
+func (x *Select) parseOrderSiblings() *Select {
+	if !x.supports(ConnectByClause) {
+		return x
+	}
+	sql := x.tempSql
+	if index := strings.LastIndex(strings.ToLower(sql), " order siblings by "); index > utils.IndexOfString(sql, consts.RightBracket, -1) {
+		x.OrderSiblings = true
+		x.tempSql = sql[:index] + " order by " + sql[index+19:]
+	}
+	return x
+}
+
+func (x *Select) beautifyHierarchy() string {
+	sql := strings.Builder{}
+	if conditions := x.StartWith; len(conditions) > 0 {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(x.align("start with"))
+		sql.WriteString(consts.Blank)
+		for i, condition := range conditions {
+			if i > 0 {
+				sql.WriteString(consts.NextLine)
+			}
+			sql.WriteString(condition.beautify(x.indent))
+		}
+	}
+	if conditions := x.ConnectBy; len(conditions) > 0 {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(x.align("connect by"))
+		sql.WriteString(consts.Blank)
+		if x.NoCycle {
+			sql.WriteString("nocycle ")
+		}
+		for i, condition := range conditions {
+			if i > 0 {
+				sql.WriteString(consts.NextLine)
+			}
+			sql.WriteString(condition.beautify(x.indent))
+		}
+	}
+	return sql.String()
+}
+
+// 解析oracle旧式外连接标记(+)，标记在值一侧（a.id = b.id(+)）为左外连接，在字段一侧为右外连接
+func (c *Condition) parseOuterJoin() {
+	if match := outerJoinPattern.FindString(c.Value); match != consts.Empty {
+		c.Value, c.OuterJoin = strings.TrimSuffix(c.Value, match), consts.LEFT
+	} else if match = outerJoinPattern.FindString(c.Name); match != consts.Empty {
+		c.Name, c.OuterJoin = strings.TrimSuffix(c.Name, match), consts.RIGHT
+	}
+}
+
+// 提取returning子句（oracle：returning id into :id，postgresql：returning *）
+func cutReturning(sql string) (string, *Output) {
+	lower := strings.ToLower(sql)
+	if index := strings.LastIndex(lower, " returning "); index > 0 {
+		if prefix := sql[:index]; strings.Count(prefix, consts.LeftBracket) == strings.Count(prefix, consts.RightBracket) {
+			return prefix, NewOutput(consts.RETURNING, strings.TrimSuffix(strings.TrimSpace(sql[index+11:]), consts.Semicolon))
+		}
+	}
+	return sql, nil
+}
+
+// 提取returning子句
+func (x *Insert) parseReturning() *Insert {
+	if x.supports(ReturningClause) {
+		x.tempSql, x.Returning = cutReturning(x.tempSql)
+	}
+	return x
+}
+
+// 提取returning子句
+func (x *Update) parseReturning() *Update {
+	if x.supports(ReturningClause) {
+		x.tempSql, x.Returning = cutReturning(x.tempSql)
+	}
+	return x
+}
+
+// 提取returning子句
+func (x *Delete) parseReturning() *Delete {
+	if x.supports(ReturningClause) {
+		x.tempSql, x.Returning = cutReturning(x.tempSql)
+	}
+	return x
+}
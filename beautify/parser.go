@@ -5,27 +5,260 @@ import (
 	"strings"
 
 	"github.com/go-xuan/sqlx/consts"
+	"github.com/go-xuan/sqlx/utils"
 )
 
-func Parse(sql string) IParser {
-	sql = strings.ReplaceAll(sql, consts.NextLine, consts.Blank)        // 移除换行
-	sql = regexp.MustCompile(`\s+`).ReplaceAllString(sql, consts.Blank) // 去除多余空格
-	sql = strings.TrimSpace(sql)                                        // 去除空格
-	switch t := strings.ToLower(sql[:6]); t {                           // 根据sql查询语句开头关键字判断sql类型
+// Option 解析选项
+type Option func(*options)
+
+// 解析选项
+type options struct {
+	dialect  Dialect        // 数据库方言
+	tolerant bool           // 容错模式
+	format   *FormatOptions // 格式化选项
+	script   string         // 语句所在的脚本，定位时以脚本为原始sql
+	offset   int            // 语句在脚本中的字节偏移
+}
+
+// WithDialect 指定数据库方言
+func WithDialect(dialect Dialect) Option {
+	return func(o *options) {
+		o.dialect = dialect
+	}
+}
+
+// WithTolerant 容错模式：无法解析的子句以及表达式作为BadClause、BadExpr保留，不再panic，
+// 美化时原样输出，诊断信息通过Diagnostics获取
+func WithTolerant() Option {
+	return func(o *options) {
+		o.tolerant = true
+	}
+}
+
+// WithFormat 指定格式化选项，未设置的数值项使用默认值
+func WithFormat(format *FormatOptions) Option {
+	return func(o *options) {
+		o.format = format.withDefaults()
+	}
+}
+
+// 语句位于脚本中的offset处，节点位置（Span）相对于整个脚本
+func inScript(script string, offset int) Option {
+	return func(o *options) {
+		o.script, o.offset = script, offset
+	}
+}
+
+func newOptions(opts ...Option) *options {
+	var o = &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Parse 解析sql，可通过选项指定数据库方言，未指定时根据语法特征自动推断，
+// 解析完成后为语句、查询、表、关联、条件、字段以及表达式节点记录其在sql中的位置（Span）
+func Parse(sql string, opts ...Option) IParser {
+	var o = newOptions(opts...)
+	var origin, source = sql, sql
+	if o.script != consts.Empty {
+		source = o.script
+	}
+	sql = strings.TrimSpace(sql)
+	if o.dialect == nil {
+		o.dialect, _ = DetectDialect(sql)
+	}
+	if firstKeyword(sql) != consts.CREATE { // 存储过程、函数、触发器需要保留原始函数体
+		var isKeyword = isCommonKeyword // 未指定方言时仅转换各方言通用的关键字
+		if o.dialect != nil {
+			isKeyword = o.dialect.IsKeyword
+		}
+		sql = utils.KeywordsToLower(sql, isKeyword)
+	}
+	var parser IParser
+	if o.tolerant {
+		parser = parseTolerant(sql, o.dialect)
+	} else {
+		parser = parse(sql, o.dialect)
+	}
+	if base, ok := parser.(interface{ setDialect(Dialect) }); ok {
+		base.setDialect(o.dialect)
+	}
+	if base, ok := parser.(interface{ setInput(string) }); ok {
+		base.setInput(origin)
+	}
+	if node, ok := parser.(locatable); ok {
+		l := newLocator(source)
+		node.locate(l, l.index(o.offset), l.index(o.offset+len(origin)))
+	}
+	if o.tolerant {
+		diagnose(parser, source)
+	}
+	if o.format != nil {
+		applyFormat(parser, o.format, origin)
+	}
+	return parser
+}
+
+// Beautify 解析并美化sql，可通过选项指定数据库方言以及格式化选项
+func Beautify(sql string, opts ...Option) string {
+	return Parse(sql, opts...).Beautify()
+}
+
+// 根据sql开头关键字解析sql，方言子句按指定方言解析，未指定方言时为nil
+func parse(sql string, dialect Dialect) IParser {
+	switch firstKeyword(sql) { // 根据sql查询语句开头关键字判断sql类型
 	case consts.SELECT:
-		return ParseSelectSQL(sql)
+		return parseSelect(compact(sql), dialect)
 	case consts.UPDATE:
-		return ParseUpdateSQL(sql)
+		return parseUpdate(compact(sql), dialect)
 	case consts.DELETE:
-		return ParseDeleteSQL(sql)
+		return parseDelete(compact(sql), dialect)
 	case consts.INSERT:
-		return ParseInsertSQL(sql)
+		return parseInsert(compact(sql), dialect)
+	case consts.MERGE:
+		return parseMerge(compact(sql), dialect)
+	case consts.CREATE:
+		return parseCreate(sql, dialect) // 存储过程、函数、触发器需要保留原始函数体
+	case consts.ALTER:
+		return ParseAlterTableSQL(compact(sql))
+	case consts.DROP:
+		return ParseDropSQL(compact(sql))
+	case consts.TRUNCATE:
+		return ParseTruncateSQL(compact(sql))
+	case consts.RENAME:
+		return ParseRenameSQL(compact(sql))
+	case consts.EXPLAIN:
+		return parseExplain(compact(sql), dialect)
+	case consts.SET:
+		return ParseSetSQL(compact(sql))
+	case consts.DECLARE:
+		return ParseDeclareSQL(compact(sql))
+	case consts.USE:
+		return ParseUseSQL(compact(sql))
+	case consts.SHOW:
+		return parseShow(compact(sql), dialect)
+	case consts.GRANT, consts.REVOKE:
+		return ParseGrantSQL(compact(sql))
+	case consts.BEGIN, consts.START, consts.COMMIT, consts.ROLLBACK, consts.SAVEPOINT, consts.RELEASE, consts.END:
+		return ParseTransactionSQL(compact(sql))
 	default:
 		panic("当前输入sql无法解析 " + sql)
 	}
 }
 
+// ParseScript 解析多语句sql脚本，按分号拆分后逐条解析，保留sql server的批处理分隔符（go、go 5）以及mysql的delimiter命令
+func ParseScript(sql string, opts ...Option) *Script {
+	var script = &Script{format: newOptions(opts...).format}
+	for _, statement := range utils.SplitScript(sql) {
+		script.Statements = append(script.Statements, Parse(statement.Text, append(opts, inScript(sql, statement.Offset))...))
+		script.Batches = append(script.Batches, statement.Batch)
+		script.Delimiters = append(script.Delimiters, statement.Delimiter)
+	}
+	return script
+}
+
+// Script 多语句sql脚本
+type Script struct {
+	Statements []IParser
+	Batches    []string // 各语句之后的批处理分隔符（sql server：go、go 5），与Statements一一对应，没有时为空
+	Delimiters []string // 各语句生效的结束分隔符（mysql：delimiter //），与Statements一一对应，为空时为分号
+
+	format *FormatOptions // 格式化选项
+}
+
+// Beautify SQL美化输出，语句之间以分号和空行分隔，批处理分隔符单独成行；
+// 结束分隔符变化时输出delimiter命令，语句以当时生效的分隔符结尾，脚本末尾恢复为分号
+func (x *Script) Beautify() string {
+	var sql = strings.Builder{}
+	var active = consts.Semicolon
+	for i, statement := range x.Statements {
+		if i > 0 {
+			sql.WriteString(consts.NextLine)
+			sql.WriteString(consts.NextLine)
+		}
+		var delimiter = consts.Semicolon
+		if i < len(x.Delimiters) && x.Delimiters[i] != consts.Empty {
+			delimiter = x.Delimiters[i]
+		}
+		if delimiter != active {
+			active = delimiter
+			sql.WriteString(x.delimiter(active))
+			sql.WriteString(consts.NextLine)
+		}
+		if delimiter != consts.Semicolon { // 自定义分隔符必须输出，否则mysql客户端无法识别语句结束
+			sql.WriteString(strings.TrimRight(statement.Beautify(), consts.Blank+consts.NextLine))
+			sql.WriteString(consts.Blank)
+			sql.WriteString(delimiter)
+		} else if x.format != nil {
+			sql.WriteString(x.format.terminate(statement.Beautify(), true))
+		} else {
+			sql.WriteString(statement.Beautify())
+			sql.WriteString(consts.Semicolon)
+		}
+		if i < len(x.Batches) && x.Batches[i] != consts.Empty {
+			sql.WriteString(consts.NextLine)
+			sql.WriteString(x.Batches[i])
+		}
+	}
+	if active != consts.Semicolon {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(x.delimiter(consts.Semicolon))
+	}
+	return sql.String()
+}
+
+// 构建delimiter命令
+func (x *Script) delimiter(delimiter string) string {
+	if x.format != nil && x.format.KeywordCase == CaseUpper {
+		return "DELIMITER " + delimiter
+	}
+	return "delimiter " + delimiter
+}
+
+// Placeholders 脚本中所有语句的绑定占位符
+func (x *Script) Placeholders() []*Placeholder {
+	var placeholders []*Placeholder
+	for _, statement := range x.Statements {
+		placeholders = append(placeholders, statement.Placeholders()...)
+	}
+	return placeholders
+}
+
 // IParser SQL解析器
 type IParser interface {
 	Beautify() string
+	Placeholders() []*Placeholder
+	Dialect() Dialect
+	Diagnostics() []*Diagnostic
+}
+
+// 压缩sql，移除换行以及多余空格
+func compact(sql string) string {
+	sql = strings.ReplaceAll(sql, consts.NextLine, consts.Blank)        // 移除换行
+	sql = regexp.MustCompile(`\s+`).ReplaceAllString(sql, consts.Blank) // 去除多余空格
+	return strings.TrimSpace(sql)                                       // 去除空格
+}
+
+// 尝试解析sql，无法解析时返回false
+func tryParse(sql string, opts ...Option) (parser IParser, ok bool) {
+	defer func() {
+		if err := recover(); err != nil {
+			parser, ok = nil, false
+		}
+	}()
+	return Parse(sql, opts...), true
+}
+
+// 获取sql开头的关键字（小写）
+func firstKeyword(sql string) string {
+	var i int
+	for ; i < len(sql); i++ {
+		if c := sql[i]; !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_') {
+			break
+		}
+	}
+	return strings.ToLower(sql[:i])
 }
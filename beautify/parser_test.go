@@ -2,6 +2,7 @@ package beautify
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -19,3 +20,802 @@ func TestInsertBeautify(t *testing.T) {
 	fmt.Println(Parse(`insert into quanchao_test (aaa,bbb,ccc,ddd) values (101,102,103,104),(201,202,203,204),(301,302,303,304);`).Beautify())
 	fmt.Println(Parse(`insert into quanchao_test (aaa,bbb,ccc,ddd) select aaa,bbb,ccc,ddd from sssss_fff`).Beautify())
 }
+
+func TestCreateTableBeautify(t *testing.T) {
+	sql := "CREATE TABLE IF NOT EXISTS `t_user` (`id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键', `name` varchar(64) NOT NULL DEFAULT '' COMMENT '名称', " +
+		"`amount` decimal(10,2) DEFAULT NULL, PRIMARY KEY (`id`), UNIQUE KEY `uk_name` (`name`), KEY `idx_amount` (`amount`) USING BTREE, " +
+		"CONSTRAINT `fk_name` FOREIGN KEY (`name`) REFERENCES `t_name` (`name`) ON DELETE SET NULL) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='用户表' PARTITION BY HASH (id)"
+	parser := Parse(sql).(*CreateTable)
+	fmt.Println(parser.Beautify())
+	if amount := parser.Columns[2]; amount.Precision != 10 || amount.Scale != 2 || !amount.Nullable {
+		t.Errorf("unexpected column %+v", amount)
+	}
+	if parser.Options.Comment != "'用户表'" || parser.Options.PartitionBy != "HASH (id)" {
+		t.Errorf("unexpected options %+v", parser.Options)
+	}
+	fmt.Println(Parse(`create table orders (id serial primary key, total numeric(12,2) check (total >= 0), created timestamp with time zone default now(), constraint uq unique (id, created))`).Beautify())
+	fmt.Println(Parse(`create table t (a, b integer primary key autoincrement, c text not null) without rowid`).Beautify())
+	like := Parse(`create table if not exists t like s`).(*CreateTable)
+	if like.Like == nil || like.Like.Name != "s" || like.Beautify() != "create table if not exists t like s" {
+		t.Errorf("unexpected create table like %q", like.Beautify())
+	}
+	if quoted := Parse("create table `order items` (id int)").(*CreateTable); quoted.Table.Name != "order items" || len(quoted.Columns) != 1 {
+		t.Errorf("unexpected quoted table %+v", quoted.Table)
+	}
+}
+
+func TestAlterTableBeautify(t *testing.T) {
+	sql := "ALTER TABLE `t_user` ADD COLUMN `age` int(11) NOT NULL DEFAULT 0 COMMENT '年龄' AFTER `name`, DROP COLUMN `old`, MODIFY `name` varchar(128) NOT NULL, " +
+		"CHANGE `a` `b` bigint FIRST, ADD UNIQUE KEY uk_ab (a, b), DROP INDEX idx_x, RENAME COLUMN c TO d, RENAME TO t_user2, COMMENT = '用户', ALTER COLUMN e SET DEFAULT 1"
+	parser := Parse(sql).(*AlterTable)
+	fmt.Println(parser.Beautify())
+	if len(parser.Actions) != 10 {
+		t.Fatalf("unexpected actions %d", len(parser.Actions))
+	}
+	if add := parser.Actions[0]; add.Type != AddColumn || add.Column.Type != "int(11)" || add.Position != "after `name`" {
+		t.Errorf("unexpected action %+v", add)
+	}
+	if change := parser.Actions[3]; change.Type != ChangeColumn || change.Name != "`a`" || change.Column.Name != "`b`" {
+		t.Errorf("unexpected action %+v", change)
+	}
+	if sql := Beautify("alter table `order items` add index idx (a)"); sql != "alter table `order items`\n      add index idx (a)" {
+		t.Errorf("unexpected quoted alter table:\n%s", sql)
+	}
+	fmt.Println(Parse(`alter table only public.orders add constraint fk_user foreign key (user_id) references users(id) on delete cascade, drop constraint if exists ck cascade, alter column total type numeric(12,2)`).Beautify())
+}
+
+func TestDDLBeautify(t *testing.T) {
+	index := Parse(`CREATE UNIQUE INDEX IF NOT EXISTS idx_a ON public.t USING btree (a, lower(b)) WHERE deleted = false`).(*CreateIndex)
+	fmt.Println(index.Beautify())
+	if index.Table.Schema != "public" || index.Table.Name != "t" || len(index.Columns) != 2 || len(index.Where) != 1 {
+		t.Errorf("unexpected index %+v", index)
+	}
+	only := Parse(`create index i on only t using gin (c)`).(*CreateIndex)
+	if !only.Only || only.Table.Name != "t" || only.Using != "gin" {
+		t.Errorf("unexpected index %+v", only)
+	}
+	fmt.Println(only.Beautify())
+	drop := Parse(`DROP TABLE IF EXISTS a, b CASCADE;`).(*Drop)
+	fmt.Println(drop.Beautify())
+	if len(drop.Tables()) != 2 || drop.Option != "cascade" {
+		t.Errorf("unexpected drop %+v", drop)
+	}
+	fmt.Println(Parse(`drop index idx_a on t`).Beautify())
+	fmt.Println(Parse(`truncate only a, b restart identity`).Beautify())
+	fmt.Println(Parse(`rename table a to b, c to d`).Beautify())
+	if sql := Beautify("create index idx on `order items` (a)"); sql != "create index idx\n    on `order items` (a)" {
+		t.Errorf("unexpected quoted index table: %s", sql)
+	}
+	if rename := Parse("rename table `a b` to c").(*Rename); rename.Renames[0].From.Name != "a b" || rename.Beautify() != "rename table `a b` to c" {
+		t.Errorf("unexpected rename %+v", rename.Renames[0].From)
+	}
+	concurrently := Parse(`drop index concurrently if exists idx`, WithDialect(PostgreSQL)).(*Drop)
+	if !concurrently.Concurrently || !concurrently.IfExists || len(concurrently.Names) != 1 || concurrently.Names[0] != "idx" {
+		t.Errorf("unexpected drop %+v", concurrently)
+	}
+}
+
+func TestViewBeautify(t *testing.T) {
+	view := Parse("CREATE OR REPLACE ALGORITHM=MERGE VIEW v_user (id, name) AS SELECT u.id, u.name FROM t_user u WHERE u.status = 'ok' WITH CASCADED CHECK OPTION").(*CreateView)
+	fmt.Println(view.Beautify())
+	if !view.OrReplace || view.Table.Name != "v_user" || len(view.Columns) != 2 || view.CheckOption != "with cascaded check option" {
+		t.Errorf("unexpected view %+v", view)
+	}
+	fmt.Println(Parse(`create materialized view mv_sales as select region, sum(amount) as total from sales group by region with no data`).Beautify())
+	fmt.Println(Parse(`drop materialized view if exists mv_sales`).Beautify())
+	if sql := Beautify("create view v as select a from t union all select b from u"); sql != "create view v as\n    select a\n      from t\n     union all\n    select b\n      from u" {
+		t.Errorf("unexpected view set operation:\n%s", sql)
+	}
+	if _, ok := tryParse("create view v as with c as (select 1 a) select a from c"); ok {
+		t.Errorf("view with common table expression should not be parsed")
+	}
+}
+
+func TestMergeBeautify(t *testing.T) {
+	sql := "MERGE INTO dim_user t USING (SELECT id, name, deleted FROM ods_user WHERE dt = '2024-01-01') s ON (t.id = s.id) " +
+		"WHEN MATCHED AND s.deleted = 1 THEN DELETE WHEN MATCHED THEN UPDATE SET t.name = s.name, t.age = s.age " +
+		"WHEN NOT MATCHED THEN INSERT (id, name, age) VALUES (s.id, s.name, s.age)"
+	merge := Parse(sql).(*Merge)
+	fmt.Println(merge.Beautify())
+	if merge.Target.Name != "dim_user" || merge.Source.Select == nil || len(merge.Branches) != 3 {
+		t.Fatalf("unexpected merge %+v", merge)
+	}
+	if insert := merge.Branches[2]; insert.Matched || insert.Action != "insert" || len(insert.Fields) != 3 || len(insert.ValueData[0]) != 3 {
+		t.Errorf("unexpected branch %+v", insert)
+	}
+	fmt.Println(Parse(`merge into t using s on (t.id = s.id) when matched then update set t.v = s.v where s.v > 0 delete where s.v < 0`).Beautify())
+	wide := Beautify("merge into t using s on (t.id = s.id) when not matched then insert (a1, a2, a3, a4, a5, a6, a7, a8, a9, a10) values (s.a1, s.a2, s.a3, s.a4, s.a5, s.a6, s.a7, s.a8, s.a9, s.a10)")
+	if !strings.HasSuffix(wide, "       insert (a1, a2, a3, a4, a5, a6, a7, a8, a9, a10)\n       values (s.a1,\n               s.a2,\n               s.a3,\n               s.a4,\n               s.a5,\n               s.a6,\n               s.a7,\n               s.a8,\n               s.a9,\n               s.a10\n              )") {
+		t.Errorf("unexpected merge insert:\n%s", wide)
+	}
+}
+
+func TestRoutineBeautify(t *testing.T) {
+	function := Parse(`CREATE OR REPLACE FUNCTION add_user(p_name text, p_age int DEFAULT 18) RETURNS integer AS $$
+DECLARE
+  v_id int;
+BEGIN
+  INSERT INTO users(name, age) VALUES (p_name, p_age) RETURNING id INTO v_id;
+  RETURN v_id;
+END;
+$$ LANGUAGE plpgsql SECURITY DEFINER;`).(*CreateRoutine)
+	fmt.Println(function.Beautify())
+	if function.Returns != "integer" || function.Language != "plpgsql" || len(function.Arguments) != 2 || function.Body.Statements != nil {
+		t.Errorf("unexpected function %+v", function)
+	}
+	procedure := Parse("CREATE PROCEDURE sync_user(IN p_id INT) BEGIN UPDATE users SET synced = 1 WHERE id = p_id; DELETE FROM tmp WHERE id = p_id; END").(*CreateRoutine)
+	fmt.Println(procedure.Beautify())
+	if len(procedure.Body.Statements) != 2 {
+		t.Errorf("unexpected procedure body %+v", procedure.Body)
+	}
+	tsql := Parse("create procedure dbo.p @a int = 1 as begin select a from t where b = @a end").(*CreateRoutine)
+	fmt.Println(tsql.Beautify())
+	if len(tsql.Arguments) != 1 || tsql.Arguments[0].Default != "1" || strings.Contains(tsql.Beautify(), "body@") {
+		t.Errorf("unexpected procedure %+v", tsql.Arguments)
+	}
+	if sql := Parse("create procedure p as begin select 1 end").Beautify(); !strings.HasPrefix(sql, "create procedure p\nas\n") {
+		t.Errorf("unexpected procedure %q", sql)
+	}
+	quoted := Parse("create function f() returns int as 'select a + 1' language sql immutable").(*CreateRoutine)
+	fmt.Println(quoted.Beautify())
+	if quoted.Language != "sql" || quoted.Body.Text != "select a + 1" || len(quoted.Options) != 1 {
+		t.Errorf("unexpected function %+v", quoted)
+	}
+	trigger := Parse(`CREATE TRIGGER trg_audit AFTER INSERT OR UPDATE OF name, age ON users FOR EACH ROW WHEN (NEW.age > 0) EXECUTE FUNCTION audit_fn()`).(*CreateTrigger)
+	fmt.Println(trigger.Beautify())
+	if trigger.Timing != "after" || len(trigger.Events) != 2 || trigger.Table.Name != "users" || trigger.ForEach != "row" {
+		t.Errorf("unexpected trigger %+v", trigger)
+	}
+	fmt.Println(Parse(`create trigger trg_log after update on t for each row insert into log (id) values (new.id)`).Beautify())
+	mssql := Parse("create trigger trg on t after insert, update as begin update t set a = 1 end").Beautify()
+	fmt.Println(mssql)
+	if !strings.HasPrefix(mssql, "create trigger trg\non t\nafter insert, update\nas\n") {
+		t.Errorf("unexpected trigger %q", mssql)
+	}
+	delimited := "DELIMITER //\ncreate procedure p() begin select 1; select 2; end //\nDELIMITER ;\nselect a from t;"
+	if sql := ParseScript(delimited).Beautify(); sql != "delimiter //\ncreate procedure p()\nbegin select 1; select 2; end //\n\ndelimiter ;\nselect a\n  from t;" {
+		t.Errorf("unexpected delimited script:\n%s", sql)
+	}
+	if sql := ParseScript("delimiter $$\ncreate function f() returns int return 1$$").Beautify(); sql != "delimiter $$\ncreate function f()\nreturns int\nreturn 1 $$\n\ndelimiter ;" {
+		t.Errorf("unexpected delimited script:\n%s", sql)
+	}
+}
+
+func TestDeleteBeautify(t *testing.T) {
+	parser := Parse("delete from t where a in (select b from u limit 1) order by id desc limit 5").(*Delete)
+	fmt.Println(parser.Beautify())
+	if len(parser.OrderBy) != 1 || parser.Limit != "5" || len(parser.Where) != 1 {
+		t.Errorf("unexpected delete %+v", parser)
+	}
+	if sql := Beautify("update t set a = 1 limit 5"); sql != "update t\n   set a = 1\n limit 5" {
+		t.Errorf("unexpected update %q", sql)
+	}
+}
+
+func TestUtilityBeautify(t *testing.T) {
+	script := ParseScript(`BEGIN;
+SET search_path TO app, public;
+set session @a := 1, @b = 'x';
+use shop;
+SHOW TABLES LIKE 'user%';
+GRANT SELECT, INSERT ON TABLE users TO reader, writer WITH GRANT OPTION;
+revoke select on t from r cascade;
+EXPLAIN ANALYZE SELECT a FROM t WHERE b = 'x;y';
+rollback to savepoint sp1;
+COMMIT;`)
+	fmt.Println(script.Beautify())
+	if len(script.Statements) != 10 {
+		t.Errorf("unexpected statements %d", len(script.Statements))
+	}
+	explain := script.Statements[7].(*Explain)
+	if !explain.Analyze || explain.Statement.(*Select).Table.Name != "t" {
+		t.Errorf("unexpected explain %+v", explain)
+	}
+	cte := &Explain{Base: NewBase("explain verbose with x as (select a from t) select a from x")}
+	cte.parsePrepare()
+	if cte.parseOptions(); len(cte.Options) != 1 || !strings.HasPrefix(cte.tempSql, "with x") {
+		t.Errorf("unexpected explain options %v", cte.Options)
+	}
+	grant := script.Statements[5].(*Grant)
+	if grant.Object != "table users" || len(grant.Grantees) != 2 || grant.Option != "with grant option" {
+		t.Errorf("unexpected grant %+v", grant)
+	}
+	if set := script.Statements[1].(*Set); set.Items[0].Value != "app, public" {
+		t.Errorf("unexpected set %+v", set.Items[0])
+	}
+}
+
+func TestPlaceholders(t *testing.T) {
+	sql := `select a::int, '?' from ${table} t where id in (#{id, jdbcType=INTEGER}, #{b}) and c = :name and d = $1 and e = ? /* ? */ and f = @p1`
+	parser := Parse(sql)
+	fmt.Println(parser.Beautify())
+	var styles []PlaceholderStyle
+	for _, placeholder := range parser.Placeholders() {
+		fmt.Printf("%d %s %s %s\n", placeholder.Position, placeholder.Style, placeholder.Name, placeholder.Text)
+		styles = append(styles, placeholder.Style)
+	}
+	if fmt.Sprint(styles) != fmt.Sprint([]PlaceholderStyle{RawStyle, HashStyle, HashStyle, ColonStyle, DollarStyle, QuestionStyle, AtStyle}) {
+		t.Errorf("unexpected placeholders %v", styles)
+	}
+	if values := parser.(*Select).Where[0].Values; len(values) != 2 || values[1] != "#{b}" {
+		t.Errorf("unexpected in values %v", values)
+	}
+	if placeholders := Parse("select a from t where data ? 'k' and tags ?| array['a'] and id = $1").Placeholders(); len(placeholders) != 1 {
+		t.Errorf("unexpected placeholders %v", placeholders)
+	}
+}
+
+func TestRebind(t *testing.T) {
+	sql := "select * from t where a = :id and b = ':x' /* :y */ and c = :name and d = :id"
+	pg, mapping, err := Rebind(Parse(sql), DollarStyle)
+	fmt.Println(pg, mapping)
+	if err != nil || pg != "select * from t where a = $1 and b = ':x' /* :y */ and c = $2 and d = $1" || fmt.Sprint(mapping) != "[0 1]" {
+		t.Errorf("unexpected rebind %s %v %v", pg, mapping, err)
+	}
+	mysql, mapping, _ := Rebind(Parse(sql), QuestionStyle)
+	fmt.Println(mysql, mapping)
+	if fmt.Sprint(mapping) != "[0 1 0]" {
+		t.Errorf("unexpected rebind %s %v", mysql, mapping)
+	}
+	named, mapping, _ := Rebind(Parse("select * from t where a = $2 and b = $1"), ColonStyle)
+	fmt.Println(named, mapping)
+	if named != "select * from t where a = :p2 and b = :p1" || fmt.Sprint(mapping) != "[1 0]" {
+		t.Errorf("unexpected rebind %s %v", named, mapping)
+	}
+	if comment, _, err := Rebind(Parse("select * from t /* ? */ where a = ?"), DollarStyle); err != nil || comment != "select * from t /* ? */ where a = $1" {
+		t.Errorf("unexpected rebind with comment %s %v", comment, err)
+	}
+	if _, _, err = Rebind(Parse("select * from t where a = :a and b = ?"), DollarStyle); err == nil {
+		t.Errorf("mixed placeholder styles should be rejected")
+	}
+	query, args, err := In("select * from t where id in (?) and name = ? and code in (?)", []int{1, 2, 3}, "x", []string{"a", "b"})
+	fmt.Println(query, args)
+	if err != nil || query != "select * from t where id in (?, ?, ?) and name = ? and code in (?, ?)" || len(args) != 6 {
+		t.Errorf("unexpected in %s %v %v", query, args, err)
+	}
+	query, args, _ = In("select * from t where id in ($1) and pid in ($1) and name = $2", []int64{7, 8}, []byte("x"))
+	fmt.Println(query, args)
+	if query != "select * from t where id in ($1, $2) and pid in ($1, $2) and name = $3" || len(args) != 3 {
+		t.Errorf("unexpected in %s %v", query, args)
+	}
+	if _, _, err = In("select * from t where id in (?) and name = ?", []int{1}); err == nil {
+		t.Errorf("missing argument should be rejected")
+	}
+	if _, _, err = In("select * from t where id in (?)", []int{}); err == nil {
+		t.Errorf("empty slice argument should be rejected")
+	}
+}
+
+func TestIdentifierBeautify(t *testing.T) {
+	parser := Parse("select o.id, \"s\".\"t\".x \"x y\" from \"my db\".\"order items\" oi join [dbo].[T] t on t.id = oi.id where oi.a = 1").(*Select)
+	fmt.Println(parser.Beautify())
+	if table := parser.Table; table.Schema != "my db" || table.Name != "order items" || table.Alias != "oi" || table.Quote != `"` {
+		t.Errorf("unexpected table %+v", table)
+	}
+	if field := parser.Fields[1]; field.Table != "s.t" || field.Name != "x" || field.column() != `"s"."t".x` {
+		t.Errorf("unexpected field %+v", field)
+	}
+	if join := parser.Joins[0].Table; join.FullName() != "[dbo].[T]" {
+		t.Errorf("unexpected join table %+v", join)
+	}
+	if table := NewTable("catalog.dbo.users"); table.Catalog != "catalog" || table.Schema != "dbo" || table.Name != "users" || table.Quote != "" {
+		t.Errorf("unexpected table %+v", table)
+	}
+	if column := NewColumn(`"a.b".c`).column(); column != `"a.b".c` {
+		t.Errorf("unexpected column %s", column)
+	}
+	if column := NewColumn("t.`a.b`.c").column(); column != "t.`a.b`.c" {
+		t.Errorf("unexpected column %s", column)
+	}
+	if name := NewTable("`my.catalog`.dbo.users").FullName(); name != "`my.catalog`.dbo.users" {
+		t.Errorf("unexpected table name %s", name)
+	}
+	if sql := Beautify(`select "a.b".c from "a.b"`); sql != `select "a.b".c`+"\n"+`  from "a.b"` {
+		t.Errorf("unexpected quoted qualifier:\n%s", sql)
+	}
+}
+
+func TestExpressionBeautify(t *testing.T) {
+	parser := Parse(`select CAST(a AS DECIMAL(10,2)) as x, a :: INT, convert(a,char), b collate utf8mb4_bin, X'FF' from t where c >= '2024-01-01'::date and d = DATE '2024-01-01' and f = timestamp  with time zone '2024-01-01 00:00:00+08' and g = TRUE`).(*Select)
+	fmt.Println(parser.Beautify())
+	if cast := parser.Fields[0].Expr.Cast; cast.Style != "cast" || cast.Expr != "a" || cast.Type != "decimal(10, 2)" {
+		t.Errorf("unexpected cast %+v", cast)
+	}
+	if field := parser.Fields[3]; field.Expr.Collate != "utf8mb4_bin" || field.Alias != "" {
+		t.Errorf("unexpected collate field %+v", field)
+	}
+	if literal := parser.Fields[4].Expr.Literal; literal.Type != HexLiteral || literal.Value != "X'FF'" {
+		t.Errorf("unexpected hex literal %+v", literal)
+	}
+	if literal := parser.Where[2].ValueExpr.Literal; literal.Type != DateTimeLiteral || literal.TypeName != "timestamp with time zone" {
+		t.Errorf("unexpected datetime literal %+v", literal)
+	}
+	if literal := NewLiteral("interval '1-2' YEAR TO MONTH"); literal.Type != IntervalLiteral || literal.Unit != "year to month" {
+		t.Errorf("unexpected interval literal %+v", literal)
+	}
+	if cast := NewCast("now() - a::int"); cast != nil {
+		t.Errorf("unexpected cast %+v", cast)
+	}
+	if cast := NewCast("convert(varchar(10), MyCol, 120)"); cast.Type != "varchar(10)" || cast.Expr != "MyCol" || cast.beautify() != "convert(varchar(10), MyCol, 120)" {
+		t.Errorf("unexpected convert %+v", cast)
+	}
+	if sql := Beautify("select cast(a as MyType), N'Abc' from t"); sql != "select cast(a as MyType), N'Abc'\n  from t" {
+		t.Errorf("unexpected expressions %q", sql)
+	}
+}
+
+func TestPostgresBeautify(t *testing.T) {
+	parser := Parse(`select distinct on (a, b) a, b, array[1,2] arr, data->>'k' as k, count(*) filter (where x > 1) as cnt, $tag$it's, from$tag$ s from t where tags @> array['x'] and name ilike any(array['%a%', '%b%']) and a is not distinct from b order by a`).(*Select)
+	fmt.Println(parser.Beautify())
+	if len(parser.DistinctOn) != 2 || len(parser.Fields) != 6 || parser.Table.Name != "t" {
+		t.Errorf("unexpected select %+v", parser)
+	}
+	var operators []string
+	for _, condition := range parser.Where {
+		operators = append(operators, condition.Operator)
+	}
+	if strings.Join(operators, ",") != "@>,ilike,is not distinct from" {
+		t.Errorf("unexpected operators %v", operators)
+	}
+	if x := Parse("select a from t where data ? 'k'", WithDialect(PostgreSQL)).(*Select); x.Where[0].Operator != "?" {
+		t.Errorf("unexpected postgresql operator %+v", x.Where[0])
+	}
+	if x := Parse("select a from t where data ? 'k'").(*Select); x.Where[0].Operator != "" {
+		t.Errorf("unexpected generic operator %+v", x.Where[0])
+	}
+	if x := Parse("select a from t where b regexp '^x'", WithDialect(PostgreSQL)).(*Select); x.Where[0].Operator != "" {
+		t.Errorf("unexpected mysql operator in postgresql %+v", x.Where[0])
+	}
+}
+
+func TestMysqlBeautify(t *testing.T) {
+	parser := Parse("select sql_calc_found_rows sql_no_cache a, b div 2 as h, c mod 3, group_concat(name order by name separator ',') names from `t` force index (idx_a) straight_join u use index for join (idx_b, idx_c) on u.id = t.id where a <=> null and d > now() - interval 1 day limit 10, 20").(*Select)
+	fmt.Println(parser.Beautify())
+	if len(parser.Modifiers) != 2 || len(parser.Fields) != 4 || parser.Fields[2].Alias != "" || parser.Fields[3].Alias != "names" {
+		t.Errorf("unexpected fields %+v", parser.Fields)
+	}
+	if parser.Table.Name != "t" || strings.Join(parser.Table.Hints, ",") != "force index (idx_a)" {
+		t.Errorf("unexpected table %+v", parser.Table)
+	}
+	if join := parser.Joins[0]; join.Type != "straight_join" || strings.Join(join.Table.Hints, ",") != "use index for join (idx_b, idx_c)" {
+		t.Errorf("unexpected join %+v", join.Table)
+	}
+	if parser.Where[0].Operator != "<=>" {
+		t.Errorf("unexpected condition %+v", parser.Where[0])
+	}
+	if x := Parse("select sql_no_cache distinct high_priority a from t").(*Select); !x.Distinct || len(x.Modifiers) != 2 || len(x.Fields) != 1 {
+		t.Errorf("unexpected modifiers %v %+v", x.Modifiers, x.Fields)
+	}
+	if x := Parse("select a from t where b ~ 'x' and c regexp 'y'", WithDialect(MySQL)).(*Select); x.Where[0].Operator != "" || x.Where[1].Operator != "regexp" {
+		t.Errorf("unexpected mysql operators %+v %+v", x.Where[0], x.Where[1])
+	}
+}
+
+func TestTsqlBeautify(t *testing.T) {
+	parser := Parse("select distinct top (10) percent with ties [a b], t.[c] name from [dbo].[order items] t with (nolock) cross apply string_split(t.tags, ',') s outer apply (select top 1 total from orders o where o.id = t.id) o where [x] = 1 order by [a b] offset 10 rows FETCH NEXT 20 ROWS ONLY").(*Select)
+	fmt.Println(parser.Beautify())
+	if top := parser.Top; top == nil || top.Count != "(10)" || !top.Percent || !top.WithTies {
+		t.Errorf("unexpected top %+v", top)
+	}
+	if parser.Table.Schema != "dbo" || parser.Table.Name != "order items" || strings.Join(parser.Table.Hints, ",") != "with (nolock)" {
+		t.Errorf("unexpected table %+v", parser.Table)
+	}
+	if len(parser.Joins) != 2 || parser.Joins[0].Type != "cross apply" || parser.Joins[1].Type != "outer apply" || parser.Joins[1].Table.Select == nil {
+		t.Errorf("unexpected joins %+v", parser.Joins)
+	}
+	if parser.Offset != "10 rows" || parser.Fetch != "next 20 rows only" {
+		t.Errorf("unexpected offset/fetch %q %q", parser.Offset, parser.Fetch)
+	}
+	if column := Parse("select topic, offset from t where offset > 10").(*Select); column.Offset != "" || len(column.Fields) != 2 || len(column.Where) != 1 {
+		t.Errorf("unexpected offset column %+v", column)
+	}
+	update := Parse("update t with (rowlock) set a = 1 output inserted.a, deleted.a into @log where id = 3").(*Update)
+	fmt.Println(update.Beautify())
+	if update.Output == nil || len(update.Output.Fields) != 2 || update.Output.Into != "@log" || len(update.Table.Hints) != 1 {
+		t.Errorf("unexpected update %+v", update.Output)
+	}
+	script := ParseScript("declare @a as int = 1, @t table (id int)\nGO\ninsert into t (a) output inserted.id values (@a)\ngo")
+	fmt.Println(script.Beautify())
+	if len(script.Statements) != 2 || len(script.Statements[0].(*Declare).Variables) != 2 || script.Statements[1].(*Insert).Output == nil {
+		t.Errorf("unexpected script %+v", script.Statements)
+	}
+	if batches := ParseScript("use db\nGO\nset nocount on;\ntruncate table t\ngo 5").Beautify(); batches != "use db;\nGO\n\nset nocount on;\n\ntruncate table t;\ngo 5" {
+		t.Errorf("unexpected batches %q", batches)
+	}
+}
+
+func TestOracleBeautify(t *testing.T) {
+	parser := Parse("select level, nvl(a.x, 0) x, decode(a.t, 1, 'one', 'other') t from emp a, dept b where a.dept_id = b.id(+) and rownum <= 10 start with parent_id is null connect by nocycle prior id = parent_id order siblings by name").(*Select)
+	fmt.Println(parser.Beautify())
+	if len(parser.Joins) != 1 || parser.Joins[0].Type != "," || parser.Joins[0].Table.Alias != "b" {
+		t.Errorf("unexpected joins %+v", parser.Joins)
+	}
+	if where := parser.Where[0]; where.Value != "b.id" || where.OuterJoin != "left" {
+		t.Errorf("unexpected outer join %+v", where)
+	}
+	if len(parser.StartWith) != 1 || len(parser.ConnectBy) != 1 || parser.ConnectBy[0].Name != "prior id" || !parser.NoCycle || !parser.OrderSiblings {
+		t.Errorf("unexpected hierarchy %+v %+v", parser.StartWith, parser.ConnectBy)
+	}
+	minus := Parse("select a from t minus select a from dual").(*Select)
+	fmt.Println(minus.Beautify())
+	if len(minus.SetOperations) != 1 || minus.SetOperations[0].Operator != "minus" || minus.SetOperations[0].Select.Table.Name != "dual" {
+		t.Errorf("unexpected set operations %+v", minus.SetOperations)
+	}
+	for _, operator := range []string{"union", "union all", "intersect", "except", "minus"} { // 嵌套与否均按首个单词对齐
+		aligned := Align(6, operator)
+		if sql := Beautify("select a from t " + operator + " select b from u"); sql != "select a\n  from t\n"+aligned+"\nselect b\n  from u" {
+			t.Errorf("unexpected set operation:\n%s", sql)
+		}
+		if sql := Beautify("select * from (select a from t " + operator + " select b from u) x"); sql != "select *\n  from (select a\n          from t\n        "+aligned+"\n        select b\n          from u) as x" {
+			t.Errorf("unexpected set operation in subquery:\n%s", sql)
+		}
+	}
+	update := Parse("update t set a = 1 where id = 2 returning a, b into :a, :b").(*Update)
+	fmt.Println(update.Beautify())
+	if update.Returning == nil || len(update.Returning.Fields) != 2 || update.Returning.Into != ":a, :b" {
+		t.Errorf("unexpected returning %+v", update.Returning)
+	}
+}
+
+func TestHiveBeautify(t *testing.T) {
+	parser := Parse("select t.id, e.c, p.k, t.`info`.`city` city from db.t tablesample (bucket 1 out of 4 on id) t lateral view explode(t.arr) e as c lateral view outer posexplode(t.m) p as k, v where t.dt = '2024-01-01' distribute by t.id sort by t.id desc limit 10").(*Select)
+	fmt.Println(parser.Beautify())
+	if parser.Table.Alias != "t" || parser.Table.Sample != "tablesample (bucket 1 out of 4 on id)" {
+		t.Errorf("unexpected table %+v", parser.Table)
+	}
+	if len(parser.LateralViews) != 2 || parser.LateralViews[0].Function != "explode(t.arr)" || !parser.LateralViews[1].Outer || len(parser.LateralViews[1].Columns) != 2 {
+		t.Errorf("unexpected lateral views %+v", parser.LateralViews)
+	}
+	if len(parser.Where) != 1 || strings.Join(parser.DistributeBy, ",") != "t.id" || strings.Join(parser.SortBy, ",") != "t.id desc" || parser.Limit != "10" {
+		t.Errorf("unexpected clauses %+v %v %v %q", parser.Where, parser.DistributeBy, parser.SortBy, parser.Limit)
+	}
+	insert := Parse("insert overwrite table db.t partition (dt='2024-01-01', hr) select a, b, hr from s").(*Insert)
+	fmt.Println(insert.Beautify())
+	if !insert.Overwrite || insert.Table.Name != "t" || len(insert.Partition) != 2 || insert.Query == nil {
+		t.Errorf("unexpected insert %+v", insert)
+	}
+	create := Parse("create external table db.t (id bigint, name string) comment 'tbl' partitioned by (dt string) stored as orc location '/data/t'").(*CreateTable)
+	fmt.Println(create.Beautify())
+	if option := create.Options; !create.External || len(option.PartitionedBy) != 1 || option.StoredAs != "orc" || option.Location != "'/data/t'" || option.Comment != "'tbl'" {
+		t.Errorf("unexpected options %+v", option)
+	}
+}
+
+func TestClickhouseBeautify(t *testing.T) {
+	parser := Parse("SELECT domain, count(*) AS c FROM hits AS h FINAL SAMPLE 0.1 ARRAY JOIN tags AS tag PREWHERE event_date = today() WHERE user_id GLOBAL IN (select id from users) GROUP BY domain WITH TOTALS ORDER BY c DESC LIMIT 5 BY domain LIMIT 100 SETTINGS max_threads = 8 FORMAT JSONEachRow").(*Select)
+	fmt.Println(parser.Beautify())
+	if table := parser.Table; table.Alias != "h" || !table.Final || table.Sample != "sample 0.1" {
+		t.Errorf("unexpected table %+v", table)
+	}
+	if len(parser.ArrayJoins) != 1 || parser.ArrayJoins[0].Left || parser.ArrayJoins[0].Fields[0] != "tags as tag" {
+		t.Errorf("unexpected array joins %+v", parser.ArrayJoins)
+	}
+	if len(parser.Prewhere) != 1 || len(parser.Where) != 1 || !parser.Where[0].Global || parser.Where[0].Name != "user_id" {
+		t.Errorf("unexpected conditions %+v %+v", parser.Prewhere, parser.Where)
+	}
+	if parser.GroupByWith != "with totals" || parser.LimitBy == nil || parser.LimitBy.Limit != "5" || parser.Limit != "100" {
+		t.Errorf("unexpected limits %q %+v %q", parser.GroupByWith, parser.LimitBy, parser.Limit)
+	}
+	if len(parser.Settings) != 1 || parser.Settings[0].Value != "8" || parser.Format != "JSONEachRow" {
+		t.Errorf("unexpected settings %+v %q", parser.Settings, parser.Format)
+	}
+	joins := Parse("select a.id from events a any left join users b on a.uid = b.id left outer join x c on c.id = a.id").(*Select)
+	fmt.Println(joins.Beautify())
+	if len(joins.Joins) != 2 || joins.Joins[0].Type != "any left" || joins.Joins[1].Type != "left outer" || joins.Table.Alias != "a" {
+		t.Errorf("unexpected joins %+v %+v", joins.Table, joins.Joins)
+	}
+	column := Parse("select final from t where final = 1").(*Select)
+	if column.Table.Final || len(column.Fields) != 1 || len(column.Where) != 1 {
+		t.Errorf("unexpected final column %+v", column)
+	}
+	for _, sql := range []string{"select id, format from t order by format desc", "select id from t where settings = 1"} {
+		for _, dialect := range []Dialect{nil, ClickHouse} {
+			if x := Parse(sql, WithDialect(dialect)).(*Select); x.Format != "" || len(x.Settings) != 0 || len(x.Where)+len(x.OrderBy) != 1 {
+				t.Errorf("unexpected settings column %+v", x)
+			}
+		}
+	}
+	if dialect, _ := DetectDialect("select id, format from t order by format desc"); dialect != nil {
+		t.Errorf("unexpected dialect %v", dialect.Name())
+	}
+}
+
+func TestDialect(t *testing.T) {
+	parser := Parse("SELECT TOP 10 NAME, 'SELECT' AS S FROM [ORDER] WHERE ID = @P1 AND K LIKE 'A%'", WithDialect(SQLServer))
+	fmt.Println(parser.Beautify())
+	if parser.Dialect() != SQLServer || parser.(*Select).Top == nil || !strings.Contains(parser.Beautify(), "'SELECT'") {
+		t.Errorf("unexpected parser %+v", parser)
+	}
+	if Parse("select 1 from t").Dialect() != nil {
+		t.Errorf("unexpected default dialect")
+	}
+	if MySQL.QuoteIdentifier("order") != "`order`" || SQLServer.QuoteIdentifier("a]b") != "[a]]b]" || PostgreSQL.QuoteIdentifier(`a"b`) != `"a""b"` {
+		t.Errorf("unexpected quoted identifier")
+	}
+	if MySQL.EscapeString(`it's \d`) != `'it''s \\d'` || PostgreSQL.EscapeString(`it's \d`) != `'it''s \d'` {
+		t.Errorf("unexpected escaped string")
+	}
+	if PostgreSQL.Placeholder(2) != "$2" || Oracle.Placeholder(1) != ":1" || SQLServer.Placeholder(3) != "@p3" || SQLite.Placeholder(1) != "?" {
+		t.Errorf("unexpected placeholder")
+	}
+	if !ClickHouse.Supports(PrewhereClause) || MySQL.Supports(ReturningClause) || Hive.Pagination() != LimitCommaPagination || !Oracle.IsKeyword("ROWNUM") {
+		t.Errorf("unexpected dialect features")
+	}
+	if DialectOf("PostgreSQL") != PostgreSQL || DialectOf("db2") != nil || len(Dialects()) != 7 {
+		t.Errorf("unexpected dialect lookup")
+	}
+	fmt.Println(Beautify("SELECT A FROM T LIMIT 10", WithDialect(MySQL)))
+	if x := Parse("select id, format from t order by format desc", WithDialect(MySQL)).(*Select); x.Format != "" || len(x.OrderBy) != 1 {
+		t.Errorf("unexpected mysql format %q %q", x.Format, x.OrderBy)
+	}
+	if x := Parse("select * from (select a, b minus from t1) x", WithDialect(MySQL)).(*Select); len(x.Table.Select.SetOperations) != 0 || len(x.Table.Select.Fields) != 2 {
+		t.Errorf("unexpected mysql minus %+v", x.Table.Select)
+	}
+	if x := Parse("select * from (select a from t1 minus select a from t2) x", WithDialect(Oracle)).(*Select); len(x.Table.Select.SetOperations) != 1 {
+		t.Errorf("unexpected oracle minus %+v", x.Table.Select)
+	}
+}
+
+func TestDetectDialect(t *testing.T) {
+	var cases = map[string]Dialect{
+		"select `name` from `user` limit 10, 20":                           MySQL,
+		"select id::text from t where name ilike 'a%' and id = $1":         PostgreSQL,
+		"select top 10 [name] from [dbo].[user] with (nolock)":             SQLServer,
+		"select * from emp where rownum <= 10 start with mgr is null":      Oracle,
+		"select a from t lateral view explode(arr) e as c distribute by a": Hive,
+		"select a from t final prewhere b = 1 settings max_threads = 8":    ClickHouse,
+		"pragma table_info('user')":                                        SQLite,
+	}
+	for sql, expect := range cases {
+		dialect, confidence := DetectDialect(sql)
+		fmt.Println(sql, "=>", dialect.Name(), confidence)
+		if dialect != expect || confidence <= 0 || confidence > 1 {
+			t.Errorf("unexpected dialect for %s: %v %v", sql, dialect.Name(), confidence)
+		}
+	}
+	if dialect, confidence := DetectDialect("select a from t where b = 'limit 1, 2'"); dialect != nil || confidence != 0 {
+		t.Errorf("unexpected dialect %v", dialect)
+	}
+	if scores := DetectDialects("select `a` from t limit 1, 2"); len(scores) < 2 || scores[0].Dialect != MySQL || scores[0].Confidence < scores[1].Confidence {
+		t.Errorf("unexpected scores %+v", scores)
+	}
+	if parser := Parse("SELECT TOP 5 A FROM [T]"); parser.Dialect() != SQLServer {
+		t.Errorf("unexpected parse dialect %v", parser.Dialect())
+	}
+}
+
+func TestTranspile(t *testing.T) {
+	var cases = []struct {
+		sql      string
+		from, to Dialect
+		contains []string
+	}{
+		{"select `id`, ifnull(name, 'n/a'), date_format(d, '%Y-%m-%d'), group_concat(tag separator ';') from `user` where ok = true limit 20, 10",
+			MySQL, PostgreSQL, []string{`"id"`, "coalesce(name, 'n/a')", "to_char(d, 'YYYY-MM-DD')", "string_agg(tag, ';')", `"user"`, "limit 10 offset 20"}},
+		{"select id from t where ok = true limit 10 offset 5", PostgreSQL, SQLServer, []string{"ok = 1", "order by (select null)", "offset 5 rows", "fetch next 10 rows only"}},
+		{"select top 10 [id] from [t]", SQLServer, MySQL, []string{"`id`", "`t`", "limit 10"}},
+		{"select id from a minus select id from b", Oracle, PostgreSQL, []string{"except"}},
+		{"insert into t (a, b) values (1, 2) on conflict (a) do update set b = excluded.b", PostgreSQL, MySQL, []string{"on duplicate key update", "b = values(b)"}},
+		{"insert ignore into t (a) values (1)", MySQL, SQLite, []string{"on conflict do nothing"}},
+	}
+	for _, c := range cases {
+		sql, untranslated := Transpile(c.sql, c.from, c.to)
+		fmt.Println(sql)
+		for _, expect := range c.contains {
+			if !strings.Contains(sql, expect) {
+				t.Errorf("transpiled sql missing %q:\n%s", expect, sql)
+			}
+		}
+		if len(untranslated) > 0 {
+			t.Errorf("unexpected untranslated %+v", untranslated[0])
+		}
+	}
+	if _, untranslated := Transpile("select a from t final prewhere b = 1", ClickHouse, MySQL); len(untranslated) != 2 {
+		t.Errorf("expect final and prewhere untranslated, got %d", len(untranslated))
+	}
+	for _, sql := range []string{"delete from t where a = 1 limit 5", "update t set a = 1 limit 5", "select a, (select max(b) from u limit 1) as m from t"} {
+		if _, untranslated := Transpile(sql, MySQL, SQLServer); len(untranslated) != 1 {
+			t.Errorf("expect limit untranslated in %q, got %d", sql, len(untranslated))
+		}
+	}
+	sql, untranslated := Transpile("select a from t where x in (select id from u limit 3)", MySQL, SQLServer)
+	if sql != "select a\n  from t\n where x in (select top 3 id\n               from u)" || len(untranslated) != 0 {
+		t.Errorf("unexpected subquery pagination:\n%s %v", sql, untranslated)
+	}
+	if _, untranslated = Transpile("select a from t where x = (select id from u limit 1)", MySQL, SQLServer); len(untranslated) != 1 {
+		t.Errorf("unexpected scalar subquery untranslated %v", untranslated)
+	}
+	stmt := Parse("select ifnull(a, 0) from t limit 10", WithDialect(MySQL))
+	if sql, _ = TranspileStatement(stmt, nil, PostgreSQL); sql != "select coalesce(a, 0)\n  from t\n limit 10" {
+		t.Errorf("unexpected statement transpile:\n%s", sql)
+	}
+	if sql, _ := Transpile(`select "x", a from t`, MySQL, PostgreSQL); !strings.Contains(sql, "'x'") {
+		t.Errorf("unexpected mysql string %s", sql)
+	}
+}
+
+func TestSpan(t *testing.T) {
+	sql := "SELECT a.id, b.name AS n\nFROM users a\n  LEFT JOIN (select id, name from b) b ON a.id = b.id\nWHERE a.x IN (1, 2)\n  AND (a.y = 'q' OR a.z is null)"
+	parser := Parse(sql).(*Select)
+	var cases = []struct {
+		span Span
+		text string
+	}{
+		{parser.Span, sql},
+		{parser.Fields[1].Span, "b.name AS n"},
+		{parser.Table.Span, "users a"},
+		{parser.Joins[0].Span, "LEFT JOIN (select id, name from b) b ON a.id = b.id"},
+		{parser.Joins[0].Table.Select.Span, "select id, name from b"},
+		{parser.Where[0].Span, "a.x IN (1, 2)"},
+		{parser.Where[1].Span, "(a.y = 'q' OR a.z is null)"},
+		{parser.Where[1].Conditions[0].ValueExpr.Span, "'q'"},
+	}
+	for _, c := range cases {
+		fmt.Println(c.span.Start, c.span.End, c.span.Text(sql))
+		if text := c.span.Text(sql); text != c.text {
+			t.Errorf("unexpected span text %q, expect %q", text, c.text)
+		}
+	}
+	if start := parser.Where[1].Span.Start; start.Line != 5 || start.Column != 7 {
+		t.Errorf("unexpected position %+v", start)
+	}
+	if table := Parse("update t set a = 1 where id = 3").(*Update).Table; table.Span.Start.Offset != 7 {
+		t.Errorf("unexpected table span %+v", table.Span)
+	}
+	script := "select a from t;\nselect b from u"
+	second := ParseScript(script).Statements[1].(*Select)
+	if text := second.Span.Text(script); text != "select b from u" || second.Span.Start.Line != 2 || second.Table.Span.Text(script) != "u" {
+		t.Errorf("unexpected script span %+v %q", second.Span, text)
+	}
+}
+
+func TestTolerant(t *testing.T) {
+	var cases = []struct {
+		sql         string
+		beautify    string
+		diagnostics int
+	}{
+		{"select a from t where", "select a\n  from t\n where", 1},
+		{"select a from t left join b on where x = 1 order by a", "select a\n  from t\n  left join b on\n where x = 1\n order by a", 1},
+		{"SELECT count( FROM t", "select count(\n  from t", 1},
+		{"select a from t where a = 1 and", "select a\n  from t\n where a = 1 and", 1},
+		{"selec a from t", "selec a from t", 1},
+		{"select a from t where b = 1", "select a\n  from t\n where b = 1", 0},
+		{"select a from where b = 1 order by", "select a from where b = 1 order by", 1},
+		{"select a from t; select", "select a from t; select", 1},
+		{"insert into t (a values", "insert into t (a values", 1},
+		{"update t set where id = 1", "update t set where id = 1", 1},
+	}
+	for _, c := range cases {
+		parser, diagnostics := ParseTolerant(c.sql)
+		sql := parser.Beautify()
+		fmt.Println(sql)
+		for _, diagnostic := range diagnostics {
+			fmt.Println(diagnostic.Message, diagnostic.Text, diagnostic.Span.Start)
+		}
+		if sql != c.beautify {
+			t.Errorf("unexpected tolerant beautify:\n%s", sql)
+		}
+		if len(diagnostics) != c.diagnostics {
+			t.Errorf("unexpected diagnostics count %d for %s", len(diagnostics), c.sql)
+		}
+		for _, diagnostic := range diagnostics {
+			if diagnostic.Span.Text(c.sql) != diagnostic.Text {
+				t.Errorf("unexpected diagnostic span %+v for %q", diagnostic.Span, diagnostic.Text)
+			}
+		}
+	}
+	parser, diagnostics := ParseTolerant("select a from t order by")
+	if partial, ok := parser.(*Partial); !ok || len(partial.BadClauses) != 1 || partial.BadClauses[0].Keyword != "order by" {
+		t.Errorf("unexpected partial %+v", parser)
+	} else if span := diagnostics[0].Span; span.Start.Offset != 16 || span.End.Offset != 24 {
+		t.Errorf("unexpected bad clause span %+v", span)
+	}
+	if _, diagnostics = ParseTolerant("select a from t where b = 1 group by"); diagnostics[0].Message != "group by子句缺少内容" {
+		t.Errorf("unexpected diagnostic message %s", diagnostics[0].Message)
+	}
+}
+
+func TestDisplayWidthAlign(t *testing.T) {
+	sql := Beautify("select u.name 姓名, u.nickname_of_user 昵称, u.id 编号 from user u")
+	fmt.Println(sql)
+	if sql != "select u.name             姓名,\n       u.nickname_of_user 昵称,\n       u.id               编号\n  from user as u" {
+		t.Errorf("unexpected sql:\n%s", sql)
+	}
+	sql = Beautify("select 用户表.名称 名, 用户表.编号 号 from 用户表")
+	fmt.Println(sql)
+	if sql != "select 用户表.名称 名,\n       用户表.编号 号\n  from 用户表" {
+		t.Errorf("unexpected sql:\n%s", sql)
+	}
+	sql = Beautify("update t set 名称 = 'a', nickname = 'b' where id = 1")
+	fmt.Println(sql)
+	if sql != "update t\n   set 名称     = 'a',\n       nickname = 'b'\n where id = 1" {
+		t.Errorf("unexpected sql:\n%s", sql)
+	}
+	if align := Align(6, "条件"); align != "  条件" {
+		t.Errorf("unexpected align %q", align)
+	}
+}
+
+func TestFormatOptions(t *testing.T) {
+	sql := "select a, b x from t u where u.a in (1, 2) and u.b = 1;"
+	cases := []struct {
+		format *FormatOptions
+		expect string
+	}{
+		{nil, "select a,\n       b x\n  from t as u\n where u.a in (1, 2)\n   and u.b = 1;"},
+		{&FormatOptions{Indent: 8, InWrap: 1}, "  select a,\n         b x\n    from t as u\n   where u.a in (1, \n                 2)\n     and u.b = 1;"},
+		{&FormatOptions{Alias: AliasAlways, Semicolon: SemicolonNever}, "select a,\n       b as x\n  from t as u\n where u.a in (1, 2)\n   and u.b = 1"},
+		{&FormatOptions{UseTabs: true, Alias: AliasNever}, "select a,\n\t   b x\n  from t u\n where u.a in (1, 2)\n   and u.b = 1;"},
+		{&FormatOptions{SelectWrap: 2, Semicolon: SemicolonAlways}, "select a,\n       b x\n  from t as u\n where u.a in (1, 2)\n   and u.b = 1;"},
+	}
+	for _, c := range cases {
+		var result string
+		if c.format == nil {
+			result = Beautify(sql)
+		} else {
+			result = Beautify(sql, WithFormat(c.format))
+		}
+		fmt.Println(result)
+		if result != c.expect {
+			t.Errorf("unexpected sql with format %+v:\n%s", c.format, result)
+		}
+	}
+	parser := ParseInsertSQL("insert into t (a, b, c) values (1, 2, 3)")
+	if result := Format(parser, &FormatOptions{InsertWrapCount: 2, Semicolon: SemicolonAlways}); result != "insert into t\n     (a,\n      b,\n      c)\nvalues\n     (1, 2, 3);" {
+		t.Errorf("unexpected insert:\n%s", result)
+	}
+	script := ParseScript("update t set a = 1; delete from t where a = 1", WithFormat(&FormatOptions{Semicolon: SemicolonNever}))
+	if result := script.Beautify(); result != "update t\n   set a = 1\n\ndelete from t\n where a = 1" {
+		t.Errorf("unexpected script:\n%s", result)
+	}
+	nested := "select a from t join (select b from u where c in (select id from v where x = 1)) w on t.a = w.b"
+	if result := Beautify(nested, WithFormat(&FormatOptions{Indent: 8, Semicolon: SemicolonNever})); result != "  select a\n    from t\n    join (select b\n            from u\n           where c in (select id\n                         from v\n                        where x = 1)) as w\n      on t.a = w.b" {
+		t.Errorf("unexpected nested subquery:\n%s", result)
+	}
+}
+
+func TestCaseStyle(t *testing.T) {
+	sql := `SELECT U.Name, Count(*) Cnt, "MixedCol" FROM Users U WHERE U.Status IN ('Aa', 'b') ORDER BY Cnt DESC`
+	cases := []struct {
+		format *FormatOptions
+		expect string
+	}{
+		{&FormatOptions{KeywordCase: CaseUpper, FunctionCase: CaseUpper},
+			"SELECT U.Name,\n       COUNT(*)   Cnt,\n       \"MixedCol\"\n  FROM Users AS U\n WHERE U.Status IN ('Aa', 'b')\n ORDER BY Cnt DESC"},
+		{&FormatOptions{KeywordCase: CaseLower, FunctionCase: CaseLower, IdentifierCase: CaseLower},
+			"select u.name,\n       count(*)   cnt,\n       \"MixedCol\"\n  from users as u\n where u.status in ('Aa', 'b')\n order by cnt desc"},
+		{&FormatOptions{KeywordCase: CasePreserve, FunctionCase: CasePreserve, IdentifierCase: CasePreserve},
+			"SELECT U.Name,\n       Count(*)   Cnt,\n       \"MixedCol\"\n  FROM Users AS U\n WHERE U.Status IN ('Aa', 'b')\n ORDER BY Cnt DESC"},
+	}
+	for _, c := range cases {
+		result := Beautify(sql, WithFormat(c.format))
+		fmt.Println(result)
+		if result != c.expect {
+			t.Errorf("unexpected sql with format %+v:\n%s", c.format, result)
+		}
+	}
+	result := Beautify("select toDate(ts), count(*) from events final", WithDialect(ClickHouse), WithFormat(&FormatOptions{KeywordCase: CaseUpper, FunctionCase: CaseUpper}))
+	if result != "SELECT toDate(ts), COUNT(*)\n  FROM events FINAL" {
+		t.Errorf("unexpected clickhouse sql:\n%s", result)
+	}
+	result = Beautify("SELECT Name FROM Events FORMAT JSONEachRow", WithDialect(ClickHouse), WithFormat(&FormatOptions{KeywordCase: CaseLower, IdentifierCase: CaseLower}))
+	if result != "select name\n  from events\nformat JSONEachRow" {
+		t.Errorf("unexpected clickhouse format:\n%s", result)
+	}
+	result = Beautify("Select A From T Where B = 1", WithFormat(&FormatOptions{KeywordCase: CasePreserve}))
+	if result != "Select A\n  From T\n Where B = 1" {
+		t.Errorf("unexpected mixed case sql:\n%s", result)
+	}
+	result = Beautify("create table t (id int auto_increment primary key comment 'x') engine=innodb comment='t'", WithFormat(&FormatOptions{KeywordCase: CaseUpper}))
+	if result != "CREATE TABLE t (\n      id INT PRIMARY KEY AUTO_INCREMENT COMMENT 'x'\n) ENGINE=innodb COMMENT='t'" {
+		t.Errorf("unexpected ddl keyword case:\n%s", result)
+	}
+	result = Beautify("alter table t add column x int", WithFormat(&FormatOptions{KeywordCase: CaseUpper}))
+	if result != "ALTER TABLE t\n      ADD COLUMN x INT" {
+		t.Errorf("unexpected alter keyword case:\n%s", result)
+	}
+	if !PostgreSQL.IsFunction("STRING_AGG") || MySQL.IsFunction("string_agg") {
+		t.Errorf("unexpected function catalogue")
+	}
+}
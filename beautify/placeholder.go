@@ -0,0 +1,255 @@
+package beautify
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+	"github.com/go-xuan/sqlx/utils"
+)
+
+// PlaceholderStyle 绑定占位符风格
+type PlaceholderStyle string
+
+const (
+	QuestionStyle PlaceholderStyle = "?"   // ?（mysql、sqlite、jdbc）
+	DollarStyle   PlaceholderStyle = "$"   // $1（postgresql）
+	ColonStyle    PlaceholderStyle = ":"   // :name（oracle、sqlx命名参数）
+	AtStyle       PlaceholderStyle = "@"   // @p1（sql server）
+	HashStyle     PlaceholderStyle = "#{}" // #{id}（mybatis预编译参数）
+	RawStyle      PlaceholderStyle = "${}" // ${table}（mybatis字符串替换）
+)
+
+// Placeholder 绑定占位符
+type Placeholder struct {
+	Style    PlaceholderStyle // 占位符风格
+	Name     string           // 参数名（:name、@p1、#{id}分别为name、p1、id，$1为1，?为空）
+	Position int              // 在语句中出现的序号，从1开始
+	Text     string           // 占位符原文
+}
+
+// NewPlaceholder 根据占位符原文初始化绑定占位符
+func NewPlaceholder(text string, position int) *Placeholder {
+	var placeholder = &Placeholder{Text: text, Position: position}
+	switch {
+	case text == "?":
+		placeholder.Style = QuestionStyle
+	case strings.HasPrefix(text, "#{"), strings.HasPrefix(text, "${"):
+		placeholder.Style = PlaceholderStyle(text[:1] + "{}")
+		// #{id, jdbcType=INTEGER}、#{user.id}
+		name, _ := utils.CutString(text[2:len(text)-1], consts.Comma)
+		placeholder.Name = strings.TrimSpace(name)
+	default:
+		placeholder.Style, placeholder.Name = PlaceholderStyle(text[:1]), text[1:]
+	}
+	return placeholder
+}
+
+// Placeholders 语句中的绑定占位符
+func (b *Base) Placeholders() []*Placeholder {
+	return b.placeholders
+}
+
+// 解析sql中所有的绑定占位符，返回替换后的sql以及还原用的替换对
+func (b *Base) parsePlaceholders(sql string) (string, []string) {
+	indices := utils.IndicesOfPlaceholders(sql)
+	if len(indices) == 0 {
+		return sql, nil
+	}
+	var oldnew []string
+	var builder = strings.Builder{}
+	var offset int
+	b.placeholders = nil
+	for i, index := range indices {
+		text := sql[index[0]:index[1]]
+		b.placeholders = append(b.placeholders, NewPlaceholder(text, i+1))
+		// 仅替换包含非单词字符的占位符（#{id, jdbcType=INTEGER}），简单占位符可直接作为单词参与解析
+		if simplePlaceholder.MatchString(text) {
+			continue
+		}
+		replaceKey := consts.ParamPrefix + strconv.Itoa(len(oldnew)/2+1) + consts.ReplaceSuffix
+		builder.WriteString(sql[offset:index[0]])
+		builder.WriteString(replaceKey)
+		offset = index[1]
+		oldnew = append(oldnew, replaceKey, text)
+	}
+	builder.WriteString(sql[offset:])
+	return builder.String(), oldnew
+}
+
+var simplePlaceholder = regexp.MustCompile(`^([?$:@]\w*|[#$]\{[\w.]+})$`)
+
+func (b *Base) setInput(input string) {
+	b.input = input
+}
+
+// 语句原文，Parse解析时为传入的sql（包含注释），直接构造时为解析的sql
+func (b *Base) text() string {
+	if b.input != consts.Empty {
+		return b.input
+	}
+	return b.originSql
+}
+
+func (p *Partial) setInput(input string) {
+	p.input = input
+}
+
+func (p *Partial) text() string {
+	return p.input
+}
+
+// Rebind 将解析后的语句中的绑定占位符转换为目标风格，返回转换后的sql以及参数映射，mapping[i]为转换后第i个参数对应的原参数下标（命名参数按首次出现顺序编号）。
+// 占位符按语句的Placeholders编号，仅替换语句原文中的占位符，引号以及注释内的内容保持不变，mybatis字符串替换（${table}）不是绑定参数同样保持不变，
+// 同一语句混用多种风格的绑定占位符时无法确定参数顺序，返回错误
+func Rebind(stmt IParser, style PlaceholderStyle) (string, []int, error) {
+	if style == RawStyle {
+		return "", nil, errors.New("不支持转换为字符串替换占位符 " + string(style))
+	}
+	var sql string
+	if x, ok := stmt.(interface{ text() string }); ok {
+		sql = x.text()
+	}
+	var placeholders = stmt.Placeholders()
+	var indices = utils.IndicesOfPlaceholders(sql)
+	if len(indices) != len(placeholders) {
+		return "", nil, errors.New("占位符与语句不匹配 " + sql)
+	}
+	var from PlaceholderStyle
+	for _, placeholder := range placeholders {
+		if placeholder.Style == RawStyle {
+			continue
+		} else if from == consts.Empty {
+			from = placeholder.Style
+		} else if placeholder.Style != from {
+			return "", nil, errors.New("不支持转换混用多种风格占位符的语句 " + sql)
+		}
+	}
+	var builder = strings.Builder{}
+	var mapping []int
+	var numbers = map[int]int{} // 原参数下标 -> 转换后参数序号
+	var counter = newArgCounter()
+	var offset int
+	for i, placeholder := range placeholders {
+		if placeholder.Style == RawStyle {
+			continue
+		}
+		arg := counter.argument(placeholder)
+		builder.WriteString(sql[offset:indices[i][0]])
+		offset = indices[i][1]
+		if style == QuestionStyle { // 按出现顺序传参，重复引用的参数需要重复传入
+			mapping = append(mapping, arg)
+			builder.WriteString(string(QuestionStyle))
+			continue
+		}
+		number, ok := numbers[arg]
+		if !ok {
+			mapping = append(mapping, arg)
+			number = len(mapping)
+			numbers[arg] = number
+		}
+		switch name := placeholder.Name; style {
+		case DollarStyle:
+			builder.WriteString(string(DollarStyle) + strconv.Itoa(number))
+		default: // 命名参数，原占位符无参数名时以p+序号命名
+			if placeholder.Style == QuestionStyle || placeholder.Style == DollarStyle {
+				name = "p" + strconv.Itoa(arg+1)
+			}
+			if style == HashStyle {
+				builder.WriteString("#{" + name + "}")
+			} else {
+				builder.WriteString(string(style) + name)
+			}
+		}
+	}
+	builder.WriteString(sql[offset:])
+	return builder.String(), mapping, nil
+}
+
+// In 展开sql中切片参数对应的占位符，例如 in (?) 传入[]int{1, 2, 3}展开为 in (?, ?, ?)，仅支持?以及$n占位符。
+// 返回展开后的sql以及平铺后的参数，参数数量与占位符不匹配或者切片参数为空时返回错误
+func In(sql string, args ...interface{}) (string, []interface{}, error) {
+	var builder = strings.Builder{}
+	var newArgs []interface{}
+	var numbers = map[int][]string{} // 原参数下标 -> 展开后的占位符（$n重复引用时复用）
+	var counter = newArgCounter()
+	var offset int
+	for i, index := range utils.IndicesOfPlaceholders(sql) {
+		placeholder := NewPlaceholder(sql[index[0]:index[1]], i+1)
+		if placeholder.Style != QuestionStyle && placeholder.Style != DollarStyle {
+			continue
+		}
+		arg := counter.argument(placeholder)
+		if arg >= len(args) {
+			return "", nil, errors.New("参数数量与占位符不匹配 " + sql)
+		}
+		builder.WriteString(sql[offset:index[0]])
+		offset = index[1]
+		values, ok := numbers[arg]
+		if !ok || placeholder.Style == QuestionStyle {
+			values = nil
+			for _, value := range expandArg(args[arg]) {
+				newArgs = append(newArgs, value)
+				if placeholder.Style == QuestionStyle {
+					values = append(values, string(QuestionStyle))
+				} else {
+					values = append(values, string(DollarStyle)+strconv.Itoa(len(newArgs)))
+				}
+			}
+			if len(values) == 0 {
+				return "", nil, errors.New("切片参数不能为空 " + sql)
+			}
+			numbers[arg] = values
+		}
+		builder.WriteString(strings.Join(values, ", "))
+	}
+	builder.WriteString(sql[offset:])
+	return builder.String(), newArgs, nil
+}
+
+// 展开切片参数，[]byte以及实现了driver.Valuer的参数视为单个参数
+func expandArg(arg interface{}) []interface{} {
+	if _, ok := arg.(driver.Valuer); ok {
+		return []interface{}{arg}
+	} else if _, ok = arg.([]byte); ok {
+		return []interface{}{arg}
+	}
+	value := reflect.ValueOf(arg)
+	if kind := value.Kind(); kind != reflect.Slice && kind != reflect.Array {
+		return []interface{}{arg}
+	}
+	var values = make([]interface{}, value.Len())
+	for i := range values {
+		values[i] = value.Index(i).Interface()
+	}
+	return values
+}
+
+// 参数计数器，计算占位符对应的原参数下标
+type argCounter struct {
+	question int            // ?占位符数量
+	names    map[string]int // 命名参数 -> 参数下标
+}
+
+func newArgCounter() *argCounter {
+	return &argCounter{names: map[string]int{}}
+}
+
+// 获取占位符对应的原参数下标，?按出现顺序，$n以及:n按序号，命名参数按首次出现顺序
+func (c *argCounter) argument(placeholder *Placeholder) int {
+	if placeholder.Style == QuestionStyle {
+		c.question++
+		return c.question - 1
+	} else if number, err := strconv.Atoi(placeholder.Name); err == nil && number > 0 {
+		return number - 1
+	} else if arg, ok := c.names[placeholder.Name]; ok {
+		return arg
+	} else {
+		c.names[placeholder.Name] = len(c.names)
+		return len(c.names) - 1
+	}
+}
@@ -0,0 +1,34 @@
+package beautify
+
+import (
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+	"github.com/go-xuan/sqlx/utils"
+)
+
+// postgresql条件运算符，按顺序匹配，长运算符需要排在其前缀运算符之前
+var postgresOperators = []string{
+	"is not distinct from", "is distinct from", "not ilike", "ilike", "not similar to", "similar to",
+	"@>", "<@", "&&", "?|", "?&", "@@", "!~*", "!~", "~*", "~", "?",
+}
+
+// 提取distinct on (a, b)，返回去除distinct on之后的字段sql
+func (x *Select) parseDistinctOn(sql string) string {
+	if !x.Distinct || !strings.HasPrefix(sql, consts.ON) {
+		return sql
+	}
+	if from, to := utils.BetweenOfString(sql, consts.LeftBracket, consts.RightBracket); from > 0 && strings.TrimSpace(sql[2:from]) == consts.Empty {
+		list, last := utils.SplitExcludeInBracket(sql[from+1:to], consts.Comma)
+		for _, value := range append(list, last) {
+			x.DistinctOn = append(x.DistinctOn, strings.TrimSpace(value))
+		}
+		return strings.TrimSpace(sql[to+1:])
+	}
+	return sql
+}
+
+// 构建distinct on (a, b)
+func (x *Select) beautifyDistinctOn() string {
+	return "on (" + strings.Join(x.DistinctOn, ", ") + ") "
+}
@@ -0,0 +1,83 @@
+package beautify
+
+import (
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+	"github.com/go-xuan/sqlx/utils"
+)
+
+// ParseRenameSQL 解析重命名表SQL（mysql：rename table a to b, c to d）
+func ParseRenameSQL(sql string, indent ...int) *Rename {
+	// sql初始化
+	var parser = &Rename{
+		Base: NewBase(sql, indent...),
+	}
+
+	// sql解析
+	parser.parsePrepare() // 解析准备
+	parser.parseTables()  // 解析重命名表
+	parser.parseFinish()  // 解析完成
+
+	return parser
+}
+
+type Rename struct {
+	Base
+	Renames []*RenamePair // 重命名表
+}
+
+// RenamePair 重命名表对
+type RenamePair struct {
+	From *Table // 原表
+	To   *Table // 新表
+}
+
+// Beautify SQL美化输出
+func (x *Rename) Beautify() string {
+	var sql = strings.Builder{}
+	sql.WriteString(consts.RENAME)
+	sql.WriteString(consts.Blank)
+	sql.WriteString(consts.TABLE)
+	sql.WriteString(consts.Blank)
+	for i, pair := range x.Renames {
+		if i > 0 {
+			sql.WriteString(consts.Comma)
+			sql.WriteString(consts.NextLine)
+			sql.WriteString(Align(13))
+		}
+		sql.WriteString(pair.From.beautify())
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.TO)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(pair.To.beautify())
+	}
+	return x.finish(sql.String())
+}
+
+// Tables 重命名涉及的表，包括原表和新表
+func (x *Rename) Tables() []*Table {
+	var tables []*Table
+	for _, pair := range x.Renames {
+		tables = append(tables, pair.From, pair.To)
+	}
+	return tables
+}
+
+// 提取重命名表
+func (x *Rename) parseTables() *Rename {
+	sql := strings.TrimSuffix(strings.TrimSpace(x.tempSql), consts.Semicolon)
+	words := skipWord(utils.SplitFieldsExcludeInBracket(sql)[1:], consts.TABLE) // 跳过rename table，引号内的表名可能包含空格
+	list, last := utils.SplitExcludeInBracket(strings.Join(words, consts.Blank), consts.Comma)
+	for _, pairSql := range append(list, last) {
+		if words = utils.SplitFieldsExcludeInBracket(pairSql); len(words) == 3 && strings.EqualFold(words[1], consts.TO) {
+			x.Renames = append(x.Renames, &RenamePair{
+				From: NewTable(words[0]),
+				To:   NewTable(words[2]),
+			})
+		} else {
+			panic("当前输入sql无法解析 " + x.originSql)
+		}
+	}
+	return x
+}
@@ -0,0 +1,392 @@
+package beautify
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+	"github.com/go-xuan/sqlx/utils"
+)
+
+// ParseCreateRoutineSQL 解析创建函数、存储过程SQL
+func ParseCreateRoutineSQL(sql string, indent ...int) *CreateRoutine {
+	return parseCreateRoutine(sql, nil, indent...)
+}
+
+// 按方言解析创建函数、存储过程SQL，未指定方言时为nil
+func parseCreateRoutine(sql string, dialect Dialect, indent ...int) *CreateRoutine {
+	// sql初始化
+	var parser = &CreateRoutine{
+		Base: NewBase(strings.TrimSpace(sql), indent...),
+	}
+	parser.dialect = dialect
+
+	// sql解析
+	parser.parseBody()      // 解析函数体，函数体需要在解析准备之前提取以保留原文
+	parser.parsePrepare()   // 解析准备
+	parser.parseHeader()    // 解析函数头
+	parser.parseArguments() // 解析参数
+	parser.parseOptions()   // 解析返回值、语言等选项
+	parser.parseFinish()    // 解析完成
+
+	return parser
+}
+
+type CreateRoutine struct {
+	Base
+	Kind      string       // 对象类型（function、procedure）
+	OrReplace bool         // 是否or replace
+	Definer   string       // 定义者（mysql：definer=`root`@`%`）
+	Name      string       // 函数名
+	Arguments []*Argument  // 参数
+	Returns   string       // 返回类型
+	Language  string       // 函数语言（sql、plpgsql）
+	Options   []string     // 其他选项原文（immutable、deterministic、security definer等）
+	Body      *RoutineBody // 函数体
+	brackets  bool         // 参数列表是否被括号包裹（sql server的存储过程参数可以不加括号）
+}
+
+// Argument 函数参数解析
+type Argument struct {
+	Mode    string // 参数模式（in、out、inout、variadic）
+	Name    string // 参数名
+	Type    string // 参数类型
+	Default string // 默认值
+}
+
+// RoutineBody 函数体解析
+type RoutineBody struct {
+	Quote      string    // 函数体引用标记（postgresql：$$、$body$、'），为空表示begin...end或单条语句
+	Text       string    // 函数体原文
+	Statements []IParser // 函数体为纯sql时按语句解析，否则为空并原样输出
+	block      bool      // 函数体是否被begin...end包裹
+	as         bool      // 函数体前是否有as关键字（sql server：as begin ... end）
+	dialect    Dialect   // 数据库方言，函数体语句按此方言解析
+}
+
+// Beautify SQL美化输出
+func (x *CreateRoutine) Beautify() string {
+	var sql = strings.Builder{}
+	sql.WriteString(consts.CREATE)
+	if x.OrReplace {
+		sql.WriteString(" or replace")
+	}
+	if x.Definer != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(x.Definer)
+	}
+	sql.WriteString(consts.Blank)
+	sql.WriteString(x.Kind)
+	sql.WriteString(consts.Blank)
+	sql.WriteString(x.Name)
+	if x.brackets {
+		sql.WriteString(consts.LeftBracket)
+	} else if len(x.Arguments) > 0 {
+		sql.WriteString(consts.Blank)
+	}
+	for i, argument := range x.Arguments {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		sql.WriteString(argument.beautify())
+	}
+	if x.brackets {
+		sql.WriteString(consts.RightBracket)
+	}
+	if x.Returns != consts.Empty {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(consts.RETURNS)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(x.Returns)
+	}
+	if x.Language != consts.Empty {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(consts.LANGUAGE)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(x.Language)
+	}
+	for _, option := range x.Options {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(option)
+	}
+	return x.finish(sql.String(), x.Body.beautify())
+}
+
+// 提取函数体，保留函数体原文
+func (x *CreateRoutine) parseBody() *CreateRoutine {
+	x.Body, x.tempSql = extractRoutineBody(x.tempSql, x.dialect)
+	return x
+}
+
+// 提取函数头（create [or replace] [definer=x] function|procedure name）
+func (x *CreateRoutine) parseHeader() *CreateRoutine {
+	sql := compact(x.tempSql)
+	words := strings.Fields(sql)
+	for i := 1; i < len(words); i++ { // 跳过create
+		switch word := strings.ToLower(words[i]); {
+		case word == "or" || word == "replace":
+			x.OrReplace = true
+		case strings.HasPrefix(word, "definer"):
+			x.Definer = x.restore(words[i])
+		case word == consts.FUNCTION || word == consts.PROCEDURE:
+			x.Kind = word
+			if i+1 < len(words) {
+				rest := strings.Join(words[i+1:], consts.Blank)
+				if index := utils.IndexOfString(rest, consts.LeftBracket); index >= 0 && !strings.Contains(strings.TrimSpace(rest[:index]), consts.Blank) {
+					x.Name, x.tempSql = strings.TrimSpace(rest[:index]), rest[index:]
+				} else { // sql server：create procedure p @a int as ...
+					x.Name, x.tempSql = words[i+1], strings.Join(words[i+2:], consts.Blank)
+				}
+			}
+			return x
+		}
+	}
+	panic("当前输入sql无法解析 " + x.originSql)
+}
+
+// 提取参数
+func (x *CreateRoutine) parseArguments() *CreateRoutine {
+	sql := x.tempSql
+	var argumentsSql string
+	if from, to := utils.BetweenOfString(sql, consts.LeftBracket, consts.RightBracket); from == 0 && to > 0 {
+		argumentsSql, sql, x.brackets = sql[1:to], sql[to+1:], true
+	} else { // sql server：不带括号的参数列表，到as、with选项或者函数体为止
+		words := utils.SplitFieldsExcludeInBracket(sql)
+		var i int
+		for ; i < len(words); i++ {
+			if word := strings.ToLower(words[i]); word == consts.AS || word == consts.WITH || word == consts.BodyPlaceholder {
+				break
+			}
+		}
+		argumentsSql, sql = strings.Join(words[:i], consts.Blank), strings.Join(words[i:], consts.Blank)
+	}
+	list, last := utils.SplitExcludeInBracket(argumentsSql, consts.Comma)
+	for _, argumentSql := range append(list, last) {
+		if words := utils.SplitFieldsExcludeInBracket(argumentSql); len(words) > 0 {
+			x.Arguments = append(x.Arguments, x.newArgument(words))
+		}
+	}
+	x.tempSql = strings.TrimSpace(sql)
+	return x
+}
+
+// 解析单个参数
+func (x *CreateRoutine) newArgument(words []string) *Argument {
+	var argument = &Argument{}
+	switch mode := strings.ToLower(words[0]); mode {
+	case consts.IN, "out", "inout", "variadic":
+		if len(words) > 1 {
+			argument.Mode, words = mode, words[1:]
+		}
+	}
+	for i, word := range words {
+		if strings.EqualFold(word, consts.DEFAULT) || word == consts.EQ {
+			argument.Default = x.restore(strings.Join(words[i+1:], consts.Blank))
+			words = words[:i]
+			break
+		}
+	}
+	// 只有类型没有参数名，例如postgresql：function f(int, text)
+	if len(words) == 1 {
+		argument.Type = words[0]
+	} else if len(words) > 1 {
+		argument.Name, argument.Type = words[0], strings.Join(words[1:], consts.Blank)
+	}
+	return argument
+}
+
+// 提取返回值、语言等选项
+func (x *CreateRoutine) parseOptions() *CreateRoutine {
+	words := utils.SplitFieldsExcludeInBracket(strings.TrimSuffix(x.tempSql, consts.Semicolon))
+	for i := 0; i < len(words); i++ {
+		switch word := strings.ToLower(words[i]); word {
+		case consts.RETURNS:
+			var returns []string
+			for i++; i < len(words); i++ {
+				if isRoutineOption(words[i]) {
+					i--
+					break
+				}
+				returns = append(returns, words[i])
+			}
+			x.Returns = x.restore(strings.Join(returns, consts.Blank))
+		case consts.LANGUAGE:
+			if i+1 < len(words) {
+				x.Language = strings.ToLower(words[i+1])
+				i++
+			}
+		case consts.AS, consts.BodyPlaceholder:
+		default:
+			var option = []string{words[i]}
+			// 多个单词组成的选项，例如not deterministic、security definer、reads sql data
+			switch word {
+			case consts.NOT, "no", "security", "contains", "reads", "modifies", "called", "parallel", "cost", "rows", "comment", "sql", consts.SET:
+				if i+1 < len(words) {
+					option = append(option, words[i+1])
+					i++
+				}
+			}
+			for ; i+1 < len(words) && !isRoutineOption(words[i+1]); i++ {
+				option = append(option, words[i+1])
+			}
+			x.Options = append(x.Options, x.restore(strings.Join(option, consts.Blank)))
+		}
+	}
+	x.tempSql = consts.Empty
+	return x
+}
+
+func (a *Argument) beautify() string {
+	var words []string
+	if a.Mode != consts.Empty {
+		words = append(words, a.Mode)
+	}
+	if a.Name != consts.Empty {
+		words = append(words, a.Name)
+	}
+	words = append(words, a.Type)
+	if a.Default != consts.Empty && strings.HasPrefix(a.Name, "@") {
+		words = append(words, consts.EQ, a.Default) // sql server：@a int = 1
+	} else if a.Default != consts.Empty {
+		words = append(words, consts.DEFAULT, a.Default)
+	}
+	return strings.Join(words, consts.Blank)
+}
+
+func (b *RoutineBody) beautify() string {
+	if b.Quote == "'" { // 单引号函数体原样输出
+		return consts.AS + " '" + b.Text + "'"
+	}
+	var sql = strings.Builder{}
+	if b.Quote != consts.Empty {
+		sql.WriteString(consts.AS)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(b.Quote)
+		sql.WriteString(consts.NextLine)
+	} else if b.as {
+		sql.WriteString(consts.AS)
+		sql.WriteString(consts.NextLine)
+	}
+	if len(b.Statements) == 0 {
+		sql.WriteString(b.Text) // 非纯sql函数体原样输出
+	} else {
+		if b.block {
+			sql.WriteString(consts.BEGIN)
+			sql.WriteString(consts.NextLine)
+		}
+		for _, statement := range b.Statements {
+			var text = statement.Beautify()
+			if b.block {
+				text = indentLines(text, routineIndent)
+			}
+			sql.WriteString(text)
+			sql.WriteString(consts.Semicolon)
+			sql.WriteString(consts.NextLine)
+		}
+		if b.block {
+			sql.WriteString(consts.END)
+		}
+	}
+	if b.Quote != consts.Empty {
+		return strings.TrimRight(sql.String(), consts.NextLine) + consts.NextLine + b.Quote
+	}
+	return strings.TrimRight(sql.String(), consts.NextLine)
+}
+
+// 函数体begin...end内语句的缩进量
+const routineIndent = 4
+
+// 提取函数体，返回函数体以及替换函数体之后的sql
+func extractRoutineBody(sql string, dialect Dialect) (*RoutineBody, string) {
+	var body = &RoutineBody{dialect: dialect}
+	// postgresql：as $$ ... $$
+	for i := 0; i < len(sql); i++ {
+		if sql[i] == '\'' { // 跳过字符串中的$
+			if end := strings.IndexByte(sql[i+1:], '\''); end >= 0 {
+				i += end + 1
+			}
+			continue
+		}
+		if tag := utils.DollarQuoteTag(sql[i:]); tag != consts.Empty {
+			if end := strings.Index(sql[i+len(tag):], tag); end >= 0 {
+				body.Quote = tag
+				body.Text = strings.Trim(sql[i+len(tag):i+len(tag)+end], "\r\n")
+				body.parseStatements()
+				return body, sql[:i] + consts.BodyPlaceholder + sql[i+len(tag)+end+len(tag):]
+			}
+		}
+	}
+	var block = regexp.MustCompile(`(?is)\b(begin|return)\b`).FindStringIndex(sql)
+	// postgresql：as 'select ...'，函数体之后的选项与函数体之前的选项一样解析
+	if match := regexp.MustCompile(`(?i)\sas\s+'`).FindStringIndex(sql); match != nil && (block == nil || match[0] < block[0]) {
+		for i := match[1]; i < len(sql); i++ {
+			if sql[i] == '\'' && i+1 < len(sql) && sql[i+1] == '\'' { // 转义的单引号
+				i++
+			} else if sql[i] == '\'' {
+				body.Quote, body.Text = "'", sql[match[1]:i]
+				return body, sql[:match[0]] + consts.Blank + consts.BodyPlaceholder + sql[i+1:]
+			}
+		}
+	}
+	// mysql、sql server：begin ... end 或者单条语句（return、as之后）
+	if match := block; match != nil {
+		body.Text = strings.TrimSuffix(strings.TrimSpace(sql[match[0]:]), consts.Semicolon)
+		body.parseStatements()
+		header := strings.TrimSpace(sql[:match[0]])
+		if as := regexp.MustCompile(`(?i)\sas$`).FindStringIndex(header); as != nil {
+			body.as, header = true, header[:as[0]]
+		}
+		return body, header + consts.Blank + consts.BodyPlaceholder
+	}
+	if match := regexp.MustCompile(`(?is)\sas\s`).FindStringIndex(sql); match != nil {
+		body.as = true
+		body.Text = strings.TrimSuffix(strings.TrimSpace(sql[match[1]:]), consts.Semicolon)
+		body.parseStatements()
+		return body, sql[:match[0]] + consts.Blank + consts.BodyPlaceholder
+	}
+	return body, sql
+}
+
+// 函数体为纯sql时按语句解析，只要有任一语句无法解析则整体原样输出
+func (b *RoutineBody) parseStatements() {
+	text := b.Text
+	if match := regexp.MustCompile(`(?is)^begin\s(.*)\send;?$`).FindStringSubmatch(strings.TrimSpace(text)); match != nil {
+		if regexp.MustCompile(`(?i)^(atomic|transaction|try|tran)\b`).MatchString(match[1]) {
+			return
+		}
+		text, b.block = match[1], true
+	}
+	var statements []IParser
+	for _, statementSql := range utils.SplitStatements(text) {
+		switch firstKeyword(statementSql) {
+		case consts.SELECT, consts.INSERT, consts.UPDATE, consts.DELETE, consts.MERGE:
+			if statement, ok := tryParse(statementSql, WithDialect(b.dialect)); ok {
+				statements = append(statements, statement)
+				continue
+			}
+		}
+		b.block = false
+		return
+	}
+	b.Statements = statements
+}
+
+// 是否函数选项关键字
+func isRoutineOption(word string) bool {
+	switch strings.ToLower(word) {
+	case consts.LANGUAGE, consts.AS, consts.BodyPlaceholder, "immutable", "stable", "volatile", "strict", "security", "deterministic",
+		consts.NOT, "contains", "reads", "modifies", "no", "comment", "called", "parallel", "cost", "rows", "leakproof", "sql", consts.SET, consts.WITH:
+		return true
+	default:
+		return false
+	}
+}
+
+// 为多行文本的每一行增加缩进
+func indentLines(text string, indent int) string {
+	lines := strings.Split(text, consts.NextLine)
+	for i, line := range lines {
+		lines[i] = Align(indent) + line
+	}
+	return strings.Join(lines, consts.NextLine)
+}
@@ -9,22 +9,40 @@ import (
 
 // ParseSelectSQL 解析查询SQL
 func ParseSelectSQL(sql string, indent ...int) *Select {
+	return parseSelect(sql, nil, indent...)
+}
+
+// 按方言解析查询SQL，未指定方言时为nil
+func parseSelect(sql string, dialect Dialect, indent ...int) *Select {
 	// sql初始化
 	var parser = &Select{
 		Base: NewBase(sql, indent...),
 	}
+	parser.dialect = dialect
 
 	// sql解析
-	parser.parsePrepare() // 解析准备
-	parser.parseLimit()   // 解析limit
-	parser.parseOrderBy() // 解析order by
-	parser.parseFields()  // 解析字段
-	parser.parseTable()   // 解析主表
-	parser.parseJoins()   // 解析关联子表
-	parser.parseWhere()   // 解析where
-	parser.parseGroupBy() // 解析group By
-	parser.parseHaving()  // 解析having
-	parser.parseFinish()  // 解析完成
+	parser.parsePrepare()       // 解析准备
+	parser.parseSettings()      // 解析settings/format
+	parser.parseSetOperations() // 解析集合运算
+	parser.parseTableHints()    // 解析表提示
+	parser.parseLimitBy()       // 解析limit by
+	parser.parseLimit()         // 解析limit
+	parser.parseOffsetFetch()   // 解析offset/fetch
+	parser.parseDistributions() // 解析cluster/distribute/sort by
+	parser.parseOrderSiblings() // 解析order siblings by
+	parser.parseOrderBy()       // 解析order by
+	parser.parseHierarchy()     // 解析层级查询
+	parser.parseLateralViews()  // 解析侧视图
+	parser.parseGroupByWith()   // 解析with totals
+	parser.parseArrayJoins()    // 解析array join
+	parser.parsePrewhere()      // 解析prewhere
+	parser.parseFields()        // 解析字段
+	parser.parseTable()         // 解析主表
+	parser.parseJoins()         // 解析关联子表
+	parser.parseWhere()         // 解析where
+	parser.parseGroupBy()       // 解析group By
+	parser.parseHaving()        // 解析having
+	parser.parseFinish()        // 解析完成
 
 	return parser
 }
@@ -40,36 +58,72 @@ type Select struct {
 	OrderBy  []string     // 排序条件
 	Limit    string       // 限数条件
 	Distinct bool         // 是否distinct
+
+	DistinctOn []string // postgresql：distinct on (a, b)
+	Modifiers  []string // mysql：sql_calc_found_rows、sql_no_cache、high_priority等查询修饰符
+	Top        *Top     // sql server：top (10) percent with ties
+	Offset     string   // 偏移条件（offset 10 rows）
+	Fetch      string   // 取数条件（fetch next 10 rows only）
+
+	StartWith     []*Condition    // oracle：层级查询起始条件（start with）
+	ConnectBy     []*Condition    // oracle：层级查询连接条件（connect by prior a = b）
+	NoCycle       bool            // oracle：connect by nocycle
+	OrderSiblings bool            // oracle：order siblings by
+	SetOperations []*SetOperation // 集合运算（union、intersect、except、minus）
+	LateralViews  []*LateralView  // hive：侧视图（lateral view explode(x) t as c）
+	ClusterBy     []string        // hive：cluster by
+	DistributeBy  []string        // hive：distribute by
+	SortBy        []string        // hive：sort by
+	ArrayJoins    []*ArrayJoin    // clickhouse：数组展开（array join arr as a）
+	Prewhere      []*Condition    // clickhouse：预过滤条件（prewhere）
+	GroupByWith   string          // clickhouse/mysql：分组修饰（with totals、with rollup）
+	LimitBy       *LimitBy        // clickhouse：分组限数（limit 5 by domain）
+	Settings      []*Field        // clickhouse：查询设置（settings max_threads = 8）
+	Format        string          // clickhouse：输出格式（format JSONEachRow）
+
+	hints   map[string][]string // 表提示，按紧邻的表名或别名归类
+	samples map[string]string   // 表采样，按紧邻的表名或别名归类
+	finals  map[string]bool     // clickhouse：final，按紧邻的表名或别名归类
 }
 
 // Beautify SQL美化输出
 func (x *Select) Beautify() string {
 	if x.simple {
-		return x.originSql
+		return x.finish(x.originSql)
 	}
 	var sql = strings.Builder{}
 	sql.WriteString(x.beautifySelect())
 	sql.WriteString(x.beautifyFrom())
+	sql.WriteString(x.beautifyLateralViews())
+	sql.WriteString(x.beautifyArrayJoins())
+	sql.WriteString(x.beautifyPrewhere())
 	sql.WriteString(x.beautifyWhere())
+	sql.WriteString(x.beautifyHierarchy())
 	sql.WriteString(x.beautifyGroupBy())
 	sql.WriteString(x.beautifyHaving())
 	sql.WriteString(x.beautifyOrderBy())
+	sql.WriteString(x.beautifyDistributions())
+	sql.WriteString(x.beautifyLimitBy())
+	sql.WriteString(x.beautifyOffsetFetch())
 	sql.WriteString(x.beautifyLimit())
-	if replacer := x.replacer; replacer != nil {
-		return replacer.Replace(sql.String())
-	} else {
-		return sql.String()
-	}
+	sql.WriteString(x.beautifySetOperations())
+	sql.WriteString(x.beautifySettings())
+	return x.finish(sql.String())
 }
 
 // 提取查询字段
 func (x *Select) parseFields() *Select {
 	sql := x.tempSql
 	if form, to := utils.BetweenOfString(sql, consts.SELECT+consts.Blank, consts.Blank+consts.FROM+consts.Blank); form >= 0 {
-		fieldsSql := sql[form+7 : to]
-		if to-form > 16 && fieldsSql[:8] == consts.DISTINCT {
-			x.Distinct = true
-			fieldsSql = fieldsSql[9:]
+		fieldsSql := x.parseModifiers(sql[form+7 : to]) // mysql查询修饰符与distinct的先后顺序不限
+		if strings.HasPrefix(fieldsSql, consts.DISTINCT+consts.Blank) {
+			x.Distinct, fieldsSql = true, fieldsSql[9:]
+			if x.supports(DistinctOnClause) {
+				fieldsSql = x.parseDistinctOn(fieldsSql)
+			}
+		}
+		if fieldsSql = x.parseModifiers(fieldsSql); x.supports(TopClause) {
+			fieldsSql = x.parseTop(fieldsSql)
 		}
 		// 判断是否有字段包含括号（子查询或者函数等内部可能会包含","逗号，从而影响字段拆分）
 		list, last := utils.SplitExcludeInBracket(fieldsSql, consts.Comma)
@@ -78,11 +132,11 @@ func (x *Select) parseFields() *Select {
 		for _, fieldSql := range list {
 			var name, alias string
 			fieldSql = strings.TrimSpace(fieldSql)
-			if i := utils.IndexOfKeywordFirst(fieldSql, consts.AS); i >= 0 {
+			if i := utils.IndexExcludeBrackets(fieldSql, consts.AS, true); i >= 0 {
 				name, alias = fieldSql[:i], fieldSql[i:]
-			} else if fieldSql[len(fieldSql)-1:] == consts.RightBracket {
+			} else if fieldSql[len(fieldSql)-1:] == consts.RightBracket || NewExpression(fieldSql) != nil {
 				name = fieldSql
-			} else if i = strings.LastIndex(fieldSql, consts.Blank); i >= 0 {
+			} else if i = utils.LastIndexExcludeQuotes(fieldSql, consts.Blank); i >= 0 && isFieldAlias(fieldSql[:i], fieldSql[i+1:]) {
 				name, alias = fieldSql[:i], fieldSql[i+1:]
 			} else {
 				name = fieldSql
@@ -90,7 +144,9 @@ func (x *Select) parseFields() *Select {
 			if utils.IndexOfString(name, consts.ReplacePrefix) >= 0 {
 				name = x.replacer.Replace(name)
 			}
-			fields = append(fields, &Field{Name: name, Alias: alias})
+			field := NewColumn(strings.TrimSpace(name))
+			field.Alias = alias
+			fields = append(fields, field)
 		}
 		x.Fields = fields
 		x.tempSql = sql[to:]
@@ -98,15 +154,53 @@ func (x *Select) parseFields() *Select {
 	return x
 }
 
+// 提取表提示（mysql：force index (idx)，sql server：with (nolock)），仅处理当前查询层级
+func (x *Select) parseTableHints() *Select {
+	x.hints, x.samples, x.finals = make(map[string][]string), make(map[string]string), make(map[string]bool)
+	x.tempSql = x.extractTableHints(x.tempSql, x.hints)
+	if x.supports(SampleClause) {
+		x.tempSql = extractTableSamples(x.tempSql, x.samples)
+	}
+	if x.supports(FinalClause) { // clickhouse：from t final sample 0.1
+		x.tempSql = extractFinalSamples(x.tempSql, x.finals, x.samples)
+	}
+	return x
+}
+
+// 为表关联表提示、表采样以及final，优先按别名匹配
+func (x *Select) attachTable(table *Table) {
+	attachTableHints(table, x.hints)
+	if table != nil && table.Select == nil {
+		for _, key := range []string{table.Alias, table.FullName()} {
+			if sample, ok := x.samples[key]; ok && key != consts.Empty {
+				table.Sample = sample
+				break
+			}
+		}
+		table.Final = x.finals[table.Alias] || x.finals[table.FullName()]
+	}
+}
+
 // 提取查询主表
 func (x *Select) parseTable() *Select {
-	x.Table, x.tempSql = ExtractTable(x.tempSql, x.indent)
+	x.Table, x.tempSql = extractTable(x.tempSql, x.indent, x.dialect)
+	x.attachTable(x.Table)
+	// 逗号分隔的多表（from a, b where a.id = b.id(+)）作为关联子表
+	for sql := strings.TrimSpace(x.tempSql); strings.HasPrefix(sql, consts.Comma); sql = strings.TrimSpace(x.tempSql) {
+		var join = &Join{Type: consts.Comma}
+		join.Table, x.tempSql = extractTable(sql[1:], x.indent, x.dialect)
+		x.attachTable(join.Table)
+		x.Joins = append(x.Joins, join)
+	}
 	return x
 }
 
 // 提取关联子表
 func (x *Select) parseJoins() *Select {
 	sql := x.tempSql
+	if x.supports(ApplyClause) {
+		sql = markApplies(sql)
+	}
 
 	var joinSqlList []string
 	joinSqlList, sql = utils.SplitExcludeInBracket(sql, consts.JOIN)
@@ -114,7 +208,7 @@ func (x *Select) parseJoins() *Select {
 	if from, to := utils.BetweenOfString(sql, consts.LeftBracket, consts.RightBracket); from == 1 {
 		lastJoin, sql = sql[:to], sql[to:]
 	}
-	if _, index := utils.ContainsKeywords(sql, consts.WHERE, consts.GROUPBY, consts.ORDERBY, consts.LIMIT); index >= 0 {
+	if _, index := utils.ContainsKeywordsExcludeBrackets(sql, consts.WHERE, consts.GROUPBY, consts.ORDERBY, consts.LIMIT); index >= 0 {
 		lastJoin, sql = lastJoin+sql[:index], sql[index:]
 	} else {
 		lastJoin, sql = lastJoin+sql, consts.Empty
@@ -125,7 +219,7 @@ func (x *Select) parseJoins() *Select {
 		var joins []*Join
 		for i, joinSql := range joinSqlList {
 			if i == 0 {
-				joinType = strings.TrimSpace(joinSql)
+				_, joinType = cutJoinType(joinSql)
 			} else {
 				var join = &Join{}
 				var space = x.indent - 1
@@ -134,29 +228,59 @@ func (x *Select) parseJoins() *Select {
 				}
 				join.Type = joinType
 
-				if hit, index := utils.LastIndexOfKeys(joinSql, consts.LEFT, consts.RIGHT, consts.INNER, consts.OUTER); index >= 0 {
-					joinType = hit
-					joinSql = joinSql[:index-1]
-				}
+				// 当前片段末尾为下一个关联的类型
+				joinSql, joinType = cutJoinType(joinSql)
 
-				if index := utils.IndexOfKeywordLast(joinSql, consts.ON); index >= 0 {
+				if index := utils.IndexOfKeywordLast(joinSql, consts.ON); index >= 0 && !isApply(join.Type) {
 					join.On, joinSql = joinSql[index+3:], joinSql[:index-1]
 				}
 
-				join.Table, _ = ExtractTable(joinSql, space+6)
+				join.Table, _ = extractTable(joinSql, space+6, x.dialect)
+				x.attachTable(join.Table)
 				joins = append(joins, join)
 			}
 		}
-		x.Joins = joins
+		x.Joins = append(x.Joins, joins...)
 		x.tempSql = sql
 	}
 	return x
 }
 
+// 截取关联片段末尾的关联类型（left、left outer、any left、cross apply），返回剩余sql以及关联类型
+func cutJoinType(sql string) (string, string) {
+	sql = strings.TrimRight(sql, consts.Blank)
+	var cut = len(sql)
+	for cut > 0 {
+		start := strings.LastIndex(sql[:cut], consts.Blank) + 1
+		if !isJoinWord(strings.ToLower(sql[start:cut])) {
+			break
+		}
+		if cut = start - 1; cut < 0 {
+			cut = 0
+		}
+	}
+	joinType := strings.ToLower(strings.TrimSpace(sql[cut:]))
+	if joinType == straightPrefix {
+		joinType = consts.STRAIGHTJOIN
+	}
+	return sql[:cut], joinType
+}
+
+// 是否关联类型单词
+func isJoinWord(word string) bool {
+	switch word {
+	case consts.LEFT, consts.RIGHT, consts.INNER, consts.OUTER, consts.CROSS, consts.APPLY, "full", "natural", straightPrefix,
+		consts.GLOBAL, consts.ANY, consts.ALL, "asof", "semi", "anti":
+		return true
+	default:
+		return false
+	}
+}
+
 // 提取查询条件
 func (x *Select) parseWhere() *Select {
 	if sql := x.tempSql; sql != "" {
-		x.Where, x.tempSql = ExtractWhere(sql)
+		x.Where, x.tempSql = extractWhere(sql, x.dialect, x.indent)
 	}
 	return x
 }
@@ -166,10 +290,10 @@ func (x *Select) parseGroupBy() *Select {
 	sql := x.tempSql
 	if index := utils.IndexOfKeywordFirst(sql, consts.GROUPBY); index >= 0 {
 		var groupBySql string
-		if _, i := utils.ContainsKeywords(sql, consts.HAVING, consts.ORDERBY, consts.LIMIT); i >= 0 {
+		if _, i := utils.ContainsKeywordsExcludeBrackets(sql, consts.HAVING, consts.ORDERBY, consts.LIMIT); i >= 0 {
 			groupBySql, sql = sql[index+9:i], sql[i:]
 		} else {
-			groupBySql, sql = sql, consts.Empty
+			groupBySql, sql = sql[index+9:], consts.Empty
 		}
 		groupBys := strings.Split(groupBySql, consts.Comma)
 		for i := range groupBys {
@@ -187,12 +311,12 @@ func (x *Select) parseHaving() *Select {
 	if index := utils.IndexOfKeywordFirst(sql, consts.HAVING); index >= 0 {
 		sql = sql[index+6:]
 		var havingSql string
-		if _, i := utils.ContainsKeywords(sql, consts.ORDERBY, consts.LIMIT); i >= 0 {
+		if _, i := utils.ContainsKeywordsExcludeBrackets(sql, consts.ORDERBY, consts.LIMIT); i >= 0 {
 			havingSql, sql = sql[:i], sql[i:]
 		} else {
 			havingSql, sql = sql, consts.Empty
 		}
-		x.Having = NewConditions(havingSql)
+		x.Having = newConditions(havingSql, x.dialect, x.indent)
 	}
 	x.tempSql = sql
 	return x
@@ -231,16 +355,36 @@ func (x *Select) parseLimit() *Select {
 func (x *Select) beautifySelect() string {
 	var sql = strings.Builder{}
 	var space = 1
-	sql.WriteString(consts.SELECT)
+	if x.nested { // 子查询首行紧随左括号，续行按缩进量对齐
+		sql.WriteString(consts.SELECT)
+	} else {
+		sql.WriteString(Align(x.format().Indent, consts.SELECT))
+	}
 	sql.WriteString(consts.Blank)
 	if x.Distinct {
 		sql.WriteString(consts.DISTINCT)
 		sql.WriteString(consts.Blank)
 		space += 9
+		if len(x.DistinctOn) > 0 {
+			distinctOn := x.beautifyDistinctOn()
+			sql.WriteString(distinctOn)
+			space += utils.DisplayWidth(distinctOn)
+		}
+	}
+	for _, modifier := range x.Modifiers {
+		sql.WriteString(modifier)
+		sql.WriteString(consts.Blank)
+		space += utils.DisplayWidth(modifier) + 1
+	}
+	if x.Top != nil {
+		top := x.Top.beautify()
+		sql.WriteString(top)
+		sql.WriteString(consts.Blank)
+		space += utils.DisplayWidth(top) + 1
 	}
 	var fieldAlign, aliasNum int
 	for _, field := range x.Fields {
-		y := len(field.Name)
+		y := utils.DisplayWidth(field.column())
 		if fieldAlign < y {
 			fieldAlign = y
 		}
@@ -252,16 +396,21 @@ func (x *Select) beautifySelect() string {
 	for i, field := range x.Fields {
 		if i > 0 {
 			sql.WriteString(consts.Comma)
-			if aliasNum > 0 || fieldNum >= 6 {
+			if aliasNum > 0 || fieldNum >= x.format().SelectWrap {
 				sql.WriteString(consts.NextLine)
 				sql.WriteString(Align(x.indent + space))
 			} else {
 				sql.WriteString(consts.Blank)
 			}
 		}
-		sql.WriteString(field.Name)
+		column := field.column()
+		sql.WriteString(column)
 		if field.Alias != consts.Empty {
-			sql.WriteString(Align(fieldAlign - len(field.Name)))
+			sql.WriteString(Align(fieldAlign - utils.DisplayWidth(column) + 1))
+			if x.format().aliasAs(false) {
+				sql.WriteString(consts.AS)
+				sql.WriteString(consts.Blank)
+			}
 			sql.WriteString(field.Alias)
 		}
 	}
@@ -273,10 +422,19 @@ func (x *Select) beautifyFrom() string {
 	sql.WriteString(consts.NextLine)
 	sql.WriteString(x.align(consts.FROM))
 	sql.WriteString(consts.Blank)
-	sql.WriteString(x.Table.beautify(true))
+	sql.WriteString(x.Table.beautify(x.format().aliasAs(true)))
 	for _, join := range x.Joins {
+		if join.Type == consts.Comma {
+			sql.WriteString(consts.Comma)
+			sql.WriteString(consts.NextLine)
+			sql.WriteString(Align(x.indent + 1))
+			sql.WriteString(join.Table.beautify(x.format().aliasAs(true)))
+			continue
+		}
 		sql.WriteString(consts.NextLine)
-		if join.Type != consts.Empty {
+		if join.Type == consts.STRAIGHTJOIN || isApply(join.Type) {
+			sql.WriteString(x.align(join.Type))
+		} else if join.Type != consts.Empty {
 			sql.WriteString(x.align(join.Type))
 			sql.WriteString(consts.Blank)
 			sql.WriteString(consts.JOIN)
@@ -284,11 +442,13 @@ func (x *Select) beautifyFrom() string {
 			sql.WriteString(x.align(consts.JOIN))
 		}
 		sql.WriteString(consts.Blank)
-		sql.WriteString(join.Table.beautify(true))
-		sql.WriteString(consts.NextLine)
-		sql.WriteString(x.align(consts.ON))
-		sql.WriteString(consts.Blank)
-		sql.WriteString(join.On)
+		sql.WriteString(join.Table.beautify(x.format().aliasAs(true)))
+		if join.On != consts.Empty {
+			sql.WriteString(consts.NextLine)
+			sql.WriteString(x.align(consts.ON))
+			sql.WriteString(consts.Blank)
+			sql.WriteString(join.On)
+		}
 	}
 	return sql.String()
 }
@@ -331,11 +491,15 @@ func (x *Select) beautifyOrderBy() string {
 	if values := x.OrderBy; len(values) > 0 {
 		sql := strings.Builder{}
 		sql.WriteString(consts.NextLine)
-		sql.WriteString(x.align(consts.ORDERBY))
+		if x.OrderSiblings {
+			sql.WriteString(x.align("order siblings by"))
+		} else {
+			sql.WriteString(x.align(consts.ORDERBY))
+		}
 		sql.WriteString(consts.Blank)
 		var max, nextLine = 0, false
 		for _, value := range values {
-			if max = max + len(value); max > 100 {
+			if max = max + utils.DisplayWidth(value); max > x.format().MaxLineWidth {
 				nextLine = true
 				break
 			}
@@ -365,7 +529,7 @@ func (x *Select) beautifyGroupBy() string {
 		sql.WriteString(consts.Blank)
 		var max, nextLine = 0, false
 		for _, value := range values {
-			if max = max + len(value); max > 100 {
+			if max = max + utils.DisplayWidth(value); max > x.format().MaxLineWidth {
 				nextLine = true
 				break
 			}
@@ -381,6 +545,10 @@ func (x *Select) beautifyGroupBy() string {
 			}
 			sql.WriteString(value)
 		}
+		if x.GroupByWith != consts.Empty {
+			sql.WriteString(consts.Blank)
+			sql.WriteString(x.GroupByWith)
+		}
 		return sql.String()
 	}
 	return ""
@@ -0,0 +1,384 @@
+package beautify
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+)
+
+// Position 原始sql中的位置
+type Position struct {
+	Offset int // 字节偏移，从0开始
+	Line   int // 行号，从1开始
+	Column int // 列号（按字节计算），从1开始
+}
+
+// Span 语法节点在原始sql（传入Parse的sql，压缩空白以及替换变量值之前）中的范围，未定位时为零值
+type Span struct {
+	Start Position // 起始位置
+	End   Position // 结束位置（节点最后一个字节的下一个字节）
+}
+
+// IsValid 是否已定位
+func (s Span) IsValid() bool {
+	return s.End.Offset > s.Start.Offset
+}
+
+// Text 截取节点在原始sql中的原文
+func (s Span) Text(sql string) string {
+	if !s.IsValid() || s.End.Offset > len(sql) {
+		return consts.Empty
+	}
+	return sql[s.Start.Offset:s.End.Offset]
+}
+
+// 可定位的语句
+type locatable interface {
+	locate(l *locator, from, to int) extent
+}
+
+// 节点在squeezed中的起止下标，未找到时均为-1
+type extent struct {
+	start, end int
+}
+
+var notFound = extent{start: -1, end: -1}
+
+func (e extent) found() bool {
+	return e.start >= 0
+}
+
+// 节点定位器：解析过程中sql经过压缩、变量值替换以及关键字转小写，节点无法直接记录位置，
+// 因此解析完成后将节点原文去除空白并转为小写，再按源码顺序在同样处理过的原始sql中依次查找
+type locator struct {
+	sql      string            // 原始sql
+	squeezed string            // 去除空白并转为小写的原始sql
+	offsets  []int             // squeezed下标对应的原始sql字节偏移
+	lines    []int             // 每行起始的字节偏移
+	replacer *strings.Replacer // 变量值还原器，取自最外层语句
+	partial  bool              // 容错模式下最外层语句包含无法解析的子句，以查找范围作为语句位置
+}
+
+func newLocator(sql string) *locator {
+	var l = &locator{sql: sql, lines: []int{0}}
+	var squeezed = make([]byte, 0, len(sql))
+	for i := 0; i < len(sql); i++ {
+		switch c := sql[i]; c {
+		case '\n':
+			l.lines = append(l.lines, i+1)
+		case ' ', '\t', '\r', '\f', '\v':
+		default:
+			if c >= 'A' && c <= 'Z' {
+				c += 'a' - 'A'
+			}
+			squeezed = append(squeezed, c)
+			l.offsets = append(l.offsets, i)
+		}
+	}
+	l.squeezed = string(squeezed)
+	return l
+}
+
+// 原始sql字节偏移对应的squeezed下标（该偏移处或之后的第一个非空白字符）
+func (l *locator) index(offset int) int {
+	return sort.SearchInts(l.offsets, offset)
+}
+
+// 去除空白并转为小写
+func (l *locator) squeeze(text string) string {
+	if l.replacer != nil {
+		text = l.replacer.Replace(text)
+	}
+	return newLocator(text).squeezed
+}
+
+// 在squeezed[from:to]中查找节点原文，匹配结果不能截断原始sql中的单词（避免表名t匹配到update）
+func (l *locator) find(text string, from, to int) extent {
+	key := strings.TrimRight(l.squeeze(text), consts.Semicolon) // 末尾字段值可能包含语句分号
+	if key == consts.Empty || from < 0 || from > to || to > len(l.squeezed) {
+		return notFound
+	}
+	for offset := from; offset < to; {
+		i := strings.Index(l.squeezed[offset:to], key)
+		if i < 0 {
+			break
+		}
+		start, end := offset+i, offset+i+len(key)
+		if !l.splitsWord(l.offsets[start], -1) && !l.splitsWord(l.offsets[end-1], 1) {
+			return extent{start: start, end: end}
+		}
+		offset = start + 1
+	}
+	return notFound
+}
+
+// 原始sql中offset处与相邻字节（step为-1时为前一个字节，为1时为后一个字节）是否同属一个单词
+func (l *locator) splitsWord(offset, step int) bool {
+	next := offset + step
+	return next >= 0 && next < len(l.sql) && isWordChar(l.sql[offset]) && isWordChar(l.sql[next])
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// 紧跟在from之后（允许间隔as）查找别名
+func (l *locator) findAlias(alias string, from, to int) int {
+	if e := l.find(alias, from, to); e.start == from || e.start == from+2 && l.squeezed[from:e.start] == consts.AS {
+		return e.end
+	}
+	return -1
+}
+
+// 范围两侧紧邻括号时扩展至括号
+func (l *locator) wrap(e extent) extent {
+	if e.start > 0 && e.end < len(l.squeezed) && l.squeezed[e.start-1] == '(' && l.squeezed[e.end] == ')' {
+		return extent{start: e.start - 1, end: e.end + 1}
+	}
+	return e
+}
+
+// 将squeezed中的起止下标转换为原始sql中的范围
+func (l *locator) span(e extent) Span {
+	if e.start < 0 || e.end <= e.start {
+		return Span{}
+	}
+	return Span{Start: l.position(l.offsets[e.start]), End: l.position(l.offsets[e.end-1] + 1)}
+}
+
+func (l *locator) position(offset int) Position {
+	line := sort.SearchInts(l.lines, offset+1) - 1
+	return Position{Offset: offset, Line: line + 1, Column: offset - l.lines[line] + 1}
+}
+
+// 定位语句整体
+func (b *Base) locate(l *locator, from, to int) extent {
+	if l.replacer == nil {
+		l.replacer = b.replacer
+	}
+	var e extent
+	if l.partial {
+		e, l.partial = extent{start: from, end: to}, false
+	} else {
+		e = l.find(b.originSql, from, to)
+	}
+	b.Span = l.span(e)
+	return e
+}
+
+func (x *Select) locate(l *locator, from, to int) extent {
+	e := x.Base.locate(l, from, to)
+	if !e.found() {
+		return notFound
+	}
+	var pos = e.start
+	for _, field := range x.Fields {
+		pos = l.advance(pos, field.locate(l, pos, e.end))
+	}
+	pos = l.advance(pos, x.Table.locate(l, pos, e.end))
+	for _, join := range x.Joins {
+		pos = l.advance(pos, join.locate(l, pos, e.end))
+	}
+	pos = l.locateConditions(x.Prewhere, pos, e.end)
+	pos = l.locateConditions(x.Where, pos, e.end)
+	pos = l.locateConditions(x.StartWith, pos, e.end)
+	pos = l.locateConditions(x.ConnectBy, pos, e.end)
+	pos = l.locateConditions(x.Having, pos, e.end)
+	for _, operation := range x.SetOperations {
+		pos = l.advance(pos, operation.Select.locate(l, pos, e.end))
+	}
+	for _, setting := range x.Settings {
+		pos = l.advance(pos, setting.locate(l, pos, e.end))
+	}
+	return e
+}
+
+func (x *Insert) locate(l *locator, from, to int) extent {
+	e := x.Base.locate(l, from, to)
+	if !e.found() {
+		return notFound
+	}
+	var pos = l.advance(e.start, x.Table.locate(l, e.start, e.end))
+	for _, field := range x.Fields {
+		pos = l.advance(pos, field.locate(l, pos, e.end))
+	}
+	if x.Query != nil {
+		pos = l.advance(pos, x.Query.locate(l, pos, e.end))
+	}
+	if x.Upsert != nil {
+		for _, field := range x.Upsert.Fields {
+			pos = l.advance(pos, field.locate(l, pos, e.end))
+		}
+	}
+	return e
+}
+
+func (x *Update) locate(l *locator, from, to int) extent {
+	e := x.Base.locate(l, from, to)
+	if !e.found() {
+		return notFound
+	}
+	var pos = l.advance(e.start, x.Table.locate(l, e.start, e.end))
+	for _, field := range x.Fields {
+		pos = l.advance(pos, field.locate(l, pos, e.end))
+	}
+	l.locateConditions(x.Where, pos, e.end)
+	return e
+}
+
+func (x *Delete) locate(l *locator, from, to int) extent {
+	e := x.Base.locate(l, from, to)
+	if !e.found() {
+		return notFound
+	}
+	l.locateConditions(x.Where, l.advance(e.start, x.Table.locate(l, e.start, e.end)), e.end)
+	return e
+}
+
+func (p *Table) locate(l *locator, from, to int) extent {
+	if p == nil {
+		return notFound
+	}
+	var e extent
+	if p.Select != nil {
+		e = l.wrap(p.Select.locate(l, from, to))
+	} else {
+		e = l.find(p.FullName(), from, to)
+	}
+	if !e.found() {
+		return notFound
+	}
+	if p.Alias != consts.Empty {
+		if end := l.findAlias(p.Alias, e.end, to); end > 0 {
+			e.end = end
+		}
+	}
+	p.Span = l.span(e)
+	return e
+}
+
+func (j *Join) locate(l *locator, from, to int) extent {
+	var keyword = j.Type
+	if keyword != consts.Comma && !strings.Contains(keyword, consts.JOIN) && !isApply(keyword) {
+		keyword += consts.JOIN
+	}
+	e := l.find(keyword, from, to)
+	if !e.found() {
+		return notFound
+	}
+	e.end = l.advance(e.end, j.Table.locate(l, e.end, to))
+	if j.On != consts.Empty {
+		e.end = l.advance(e.end, l.find(j.On, e.end, to))
+	}
+	j.Span = l.span(e)
+	return e
+}
+
+func (f *Field) locate(l *locator, from, to int) extent {
+	var e extent
+	if f.Expr != nil {
+		e = f.Expr.locate(l, from, to)
+	} else {
+		e = l.find(f.column(), from, to)
+	}
+	if !e.found() {
+		return notFound
+	}
+	if f.Alias != consts.Empty {
+		if end := l.findAlias(f.Alias, e.end, to); end > 0 {
+			e.end = end
+		}
+	}
+	if f.Value != consts.Empty {
+		e.end = l.advance(e.end, l.find(f.Value, e.end, to))
+	}
+	f.Span = l.span(e)
+	return e
+}
+
+func (e *Expression) locate(l *locator, from, to int) extent {
+	at := l.find(e.Text, from, to)
+	if !at.found() {
+		return notFound
+	}
+	if e.Collate != consts.Empty {
+		at.end = l.advance(at.end, l.find(consts.COLLATE+e.Collate, at.end, to))
+	}
+	e.Span = l.span(at)
+	return at
+}
+
+// 按顺序定位条件，返回最后一个条件之后的查找位置
+func (l *locator) locateConditions(conditions []*Condition, from, to int) int {
+	for _, condition := range conditions {
+		from = l.advance(from, condition.locate(l, from, to))
+	}
+	return from
+}
+
+func (c *Condition) locate(l *locator, from, to int) extent {
+	var e = notFound
+	if len(c.Conditions) > 0 { // 联合子条件
+		for _, condition := range c.Conditions {
+			if sub := condition.locate(l, l.advance(from, e), to); sub.found() {
+				if !e.found() {
+					e.start = sub.start
+				}
+				e.end = sub.end
+			}
+		}
+		if e.found() {
+			e = l.wrap(e)
+		}
+	} else {
+		if c.NameExpr != nil {
+			e = c.NameExpr.locate(l, from, to)
+		} else {
+			e = l.find(c.Name, from, to)
+		}
+		if !e.found() {
+			return notFound
+		}
+		e.end = l.advance(e.end, l.find(c.Operator, e.end, to))
+		switch {
+		case c.Select != nil:
+			e.end = l.advance(e.end, c.Select.locate(l, e.end, to))
+		case len(c.Values) > 0:
+			for _, value := range c.Values {
+				e.end = l.advance(e.end, l.find(value, e.end, to))
+			}
+		case c.ValueExpr != nil:
+			e.end = l.advance(e.end, c.ValueExpr.locate(l, e.end, to))
+		case c.Value != consts.Empty:
+			e.end = l.advance(e.end, l.find(c.Value, e.end, to))
+		}
+		if c.Operator == consts.IN || c.Operator == consts.NOTIN {
+			e.end = l.adjacent(e.end, consts.RightBracket, to)
+		}
+		if c.OuterJoin == consts.LEFT {
+			e.end = l.adjacent(e.end, outerJoinMark, to)
+		}
+	}
+	if !e.found() {
+		return notFound
+	}
+	c.Span = l.span(e)
+	return e
+}
+
+// 节点定位成功时前移查找位置
+func (l *locator) advance(pos int, e extent) int {
+	if e.found() {
+		return e.end
+	}
+	return pos
+}
+
+// 紧邻pos之后为text时前移至text之后
+func (l *locator) adjacent(pos int, text string, to int) int {
+	if e := l.find(text, pos, to); e.start == pos {
+		return e.end
+	}
+	return pos
+}
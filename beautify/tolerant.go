@@ -0,0 +1,457 @@
+package beautify
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+	"github.com/go-xuan/sqlx/utils"
+)
+
+// 容错模式下拆分子句的关键字，多词关键字需要位于其前缀之前
+var clauseKeywords = [][]string{
+	{"left", "outer", "join"}, {"right", "outer", "join"}, {"full", "outer", "join"},
+	{"left", "join"}, {"right", "join"}, {"inner", "join"}, {"cross", "join"}, {"full", "join"}, {"join"},
+	{"from"}, {"where"}, {"group", "by"}, {"having"}, {"order", "by"}, {"limit"}, {"offset"}, {"fetch"},
+	{"union", "all"}, {"union"}, {"intersect"}, {"except"}, {"minus"},
+	{"set"}, {"values"}, {"returning"}, {"on", "duplicate", "key", "update"}, {"on", "conflict"},
+}
+
+// ParseTolerant 以容错模式解析sql，返回尽力解析的语法树以及诊断信息
+func ParseTolerant(sql string, opts ...Option) (IParser, []*Diagnostic) {
+	parser := Parse(sql, append(opts, WithTolerant())...)
+	return parser, parser.Diagnostics()
+}
+
+// Diagnostic 容错解析的诊断信息
+type Diagnostic struct {
+	Message string // 错误信息
+	Text    string // 出错的sql片段
+	Span    Span   // 出错片段在原始sql中的位置
+}
+
+// BadExpr 无法解析的表达式（字段、条件），美化时原样输出
+type BadExpr struct {
+	Text    string // 表达式原文
+	Message string // 错误信息
+}
+
+// BadClause 无法解析的子句，美化时原样输出
+type BadClause struct {
+	Keyword string // 子句关键字，语句开头无法解析时为空
+	Text    string // 子句原文
+	Message string // 错误信息
+	Span    Span   // 原始sql中的位置
+
+	before string // 紧随其后的可解析子句关键字，美化时插入到该子句之前
+}
+
+// Partial 容错模式下部分子句无法解析的语句
+type Partial struct {
+	Statement  IParser      // 可解析子句组成的语句，全部无法解析时为nil
+	BadClauses []*BadClause // 无法解析的子句
+
+	segments []*segment     // 全部子句
+	dialect  Dialect        // 数据库方言
+	format   *FormatOptions // 格式化选项
+	root     bool           // 是否最外层语句
+	source   string         // 原始sql，保留大小写时参照其中的写法
+	input    string         // 传入Parse的原始sql（包含注释）
+}
+
+// 子句片段
+type segment struct {
+	keyword string     // 子句关键字
+	text    string     // 子句sql
+	bad     *BadClause // 无法解析时对应的子句
+}
+
+// 解析sql，解析失败时返回错误信息
+func recoverParse(sql string, dialect Dialect) (parser IParser, err interface{}) {
+	defer func() {
+		if err = recover(); err != nil {
+			parser = nil
+		}
+	}()
+	return parse(sql, dialect), nil
+}
+
+// 容错解析：整体解析失败时按子句拆分，逐个追加子句尝试解析，无法解析的子句作为BadClause原样保留
+func parseTolerant(sql string, dialect Dialect) IParser {
+	if parser, err := recoverParse(sql, dialect); err == nil && !incomplete(parser) {
+		return parser
+	}
+	var partial = &Partial{}
+	var good, pending []string
+	for _, seg := range splitClauses(compact(sql)) {
+		candidate := strings.Join(append(append(append([]string{}, good...), pending...), seg.text), consts.Blank)
+		if parser, err := recoverParse(candidate, dialect); err == nil && !incomplete(parser) {
+			partial.Statement, good, pending = parser, append(append(good, pending...), seg.text), nil
+		} else if len(good) == 0 { // 语句开头尚不完整，与后续子句合并后继续尝试
+			pending = append(pending, seg.text)
+		} else {
+			seg.bad = &BadClause{Keyword: seg.keyword, Message: clauseMessage(seg)}
+		}
+		if last := len(partial.segments) - 1; seg.bad != nil && last >= 0 && partial.segments[last].bad != nil { // 合并相邻的无法解析子句
+			partial.segments[last].text += consts.Blank + seg.text
+		} else {
+			partial.segments = append(partial.segments, seg)
+		}
+	}
+	if len(good) == 0 { // 全部无法解析
+		var message = "无法解析sql"
+		if keyword := firstKeyword(sql); keyword != consts.Empty {
+			message = "无法解析" + keyword + "语句"
+		}
+		partial.segments = []*segment{{text: compact(sql), bad: &BadClause{Message: message}}}
+	}
+	var before string
+	for i := len(partial.segments) - 1; i >= 0; i-- { // 记录其后紧随的可解析子句
+		if seg := partial.segments[i]; seg.bad == nil {
+			before = seg.keyword
+		} else {
+			seg.bad.Text, seg.bad.before = seg.text, before
+		}
+	}
+	for _, seg := range partial.segments {
+		if seg.bad != nil {
+			partial.BadClauses = append(partial.BadClauses, seg.bad)
+		}
+	}
+	return partial
+}
+
+// 子句级别的错误信息，子句只有关键字时提示缺少内容
+func clauseMessage(seg *segment) string {
+	if strings.EqualFold(seg.text, seg.keyword) {
+		return seg.keyword + "子句缺少内容"
+	}
+	return "无法解析" + seg.keyword + "子句"
+}
+
+// 解析结果是否缺少必要部分（查询缺少字段、更新以及删除缺少表）或者误将子句关键字解析为表，此时视为解析失败
+func incomplete(parser IParser) bool {
+	switch x := parser.(type) {
+	case *Select:
+		if len(x.Fields) == 0 || misparsedTable(x.Table) {
+			return true
+		}
+		for _, join := range x.Joins {
+			if misparsedTable(join.Table) {
+				return true
+			}
+		}
+		return false
+	case *Update:
+		if x.Table == nil || x.Table.Name == consts.Empty && x.Table.Select == nil || misparsedTable(x.Table) || len(x.Fields) == 0 {
+			return true
+		}
+		for _, field := range x.Fields {
+			if field.Name == consts.Empty { // set之后缺少字段（update t set where id = 1）
+				return true
+			}
+		}
+		return false
+	case *Delete:
+		return x.Table == nil || x.Table.Name == consts.Empty && x.Table.Select == nil || misparsedTable(x.Table)
+	case *Insert:
+		if x.Table == nil || x.Table.Name == consts.Empty {
+			return true
+		}
+		for _, field := range x.Fields {
+			if unbalanced(field.Name) { // 字段列表括号未闭合（insert into t (a values）
+				return true
+			}
+		}
+		for _, values := range x.ValueData {
+			for _, value := range values {
+				if unbalanced(value) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// 括号是否未成对
+func unbalanced(sql string) bool {
+	return strings.Count(sql, consts.LeftBracket) != strings.Count(sql, consts.RightBracket)
+}
+
+// 表名或别名为子句关键字或者包含运算符、分号（from where b = 1、from t; select），
+// 说明表之后的子句未能识别，此时不能以改写后的结果输出
+func misparsedTable(table *Table) bool {
+	if table == nil {
+		return false
+	}
+	for _, word := range []string{table.Name, table.Alias} {
+		if strings.ContainsAny(word, "=;") || strings.EqualFold(word, consts.SELECT) || matchClauseKeyword([]string{word}) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// 按顶层子句关键字拆分sql，忽略字符串以及括号内的内容
+func splitClauses(sql string) []*segment {
+	masked, values := utils.ExtractValuesInSql(sql)
+	var replacer = strings.NewReplacer(values...)
+	var words = strings.Split(masked, consts.Blank)
+	var segments []*segment
+	var current = &segment{keyword: firstKeyword(sql)}
+	var start, depth int
+	for i := 0; i < len(words); i++ {
+		if depth == 0 && i > 0 {
+			if keyword := matchClauseKeyword(words[i:]); keyword != nil {
+				current.text = replacer.Replace(strings.Join(words[start:i], consts.Blank))
+				segments = append(segments, current)
+				current, start = &segment{keyword: strings.Join(keyword, consts.Blank)}, i
+			}
+		}
+		depth += strings.Count(words[i], consts.LeftBracket) - strings.Count(words[i], consts.RightBracket)
+	}
+	current.text = replacer.Replace(strings.Join(words[start:], consts.Blank))
+	return append(segments, current)
+}
+
+// 匹配开头的子句关键字
+func matchClauseKeyword(words []string) []string {
+	for _, keyword := range clauseKeywords {
+		if len(words) < len(keyword) {
+			continue
+		}
+		var match = true
+		for i, word := range keyword {
+			if !strings.EqualFold(words[i], word) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return keyword
+		}
+	}
+	return nil
+}
+
+// Beautify SQL美化输出，无法解析的子句原样插入到其后紧随的可解析子句之前
+func (p *Partial) Beautify() string {
+	if p.Statement == nil {
+		var texts []string
+		for _, clause := range p.BadClauses {
+			texts = append(texts, clause.Text)
+		}
+		return p.finish(strings.Join(texts, consts.Blank))
+	}
+	var format = p.format
+	if format == nil {
+		format = defaultFormat
+	}
+	var lines = strings.Split(p.Statement.Beautify(), consts.NextLine)
+	for _, clause := range p.BadClauses {
+		var at = len(lines)
+		if clause.before != consts.Empty {
+			for i, line := range lines {
+				if trimmed := strings.TrimLeft(line, consts.Blank); strings.HasPrefix(trimmed, clause.before) && len(line)-len(trimmed) <= format.Indent {
+					at = i
+					break
+				}
+			}
+		}
+		var text = clause.Text
+		if clause.Keyword != consts.Empty { // 与其他子句一样按关键字右对齐
+			text = Align(format.Indent, text)
+		}
+		lines = append(lines[:at], append([]string{text}, lines[at:]...)...)
+	}
+	return p.finish(strings.Join(lines, consts.NextLine))
+}
+
+// 最外层语句按格式化选项转换大小写、行首缩进以及处理末尾分号
+func (p *Partial) finish(sql string) string {
+	if !p.root || p.format == nil {
+		return sql
+	}
+	sql = p.format.convertCase(sql, p.source, p.dialect)
+	return p.format.terminate(p.format.indentTabs(sql), false)
+}
+
+// Placeholders 可解析部分的绑定占位符
+func (p *Partial) Placeholders() []*Placeholder {
+	if p.Statement == nil {
+		return nil
+	}
+	return p.Statement.Placeholders()
+}
+
+// Dialect 解析时指定的数据库方言
+func (p *Partial) Dialect() Dialect {
+	return p.dialect
+}
+
+func (p *Partial) setDialect(dialect Dialect) {
+	p.dialect = dialect
+	if base, ok := p.Statement.(interface{ setDialect(Dialect) }); ok {
+		base.setDialect(dialect)
+	}
+}
+
+// Diagnostics 无法解析的子句以及可解析部分中无法解析的表达式，按位置排序
+func (p *Partial) Diagnostics() []*Diagnostic {
+	var diagnostics []*Diagnostic
+	for _, clause := range p.BadClauses {
+		diagnostics = append(diagnostics, &Diagnostic{Message: clause.Message, Text: clause.Text, Span: clause.Span})
+	}
+	if p.Statement != nil {
+		diagnostics = append(diagnostics, p.Statement.Diagnostics()...)
+	}
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		return diagnostics[i].Span.Start.Offset < diagnostics[j].Span.Start.Offset
+	})
+	return diagnostics
+}
+
+// 按子句顺序定位，无法解析的子句使用原始sql中的原文
+func (p *Partial) locate(l *locator, from, to int) extent {
+	var pos = from
+	for _, seg := range p.segments {
+		e := l.find(seg.text, pos, to)
+		if seg.bad != nil && e.found() {
+			seg.bad.Span = l.span(e)
+			seg.bad.Text = seg.bad.Span.Text(l.sql)
+		}
+		pos = l.advance(pos, e)
+	}
+	if node, ok := p.Statement.(locatable); ok {
+		l.partial = true // 可解析子句之间夹杂无法解析的子句，语句整体无法直接查找
+		node.locate(l, from, to)
+	}
+	return extent{start: from, end: to}
+}
+
+// Diagnostics 容错模式下无法解析的表达式
+func (b *Base) Diagnostics() []*Diagnostic {
+	return b.diagnostics
+}
+
+// 检查语法树中无法解析的字段以及条件，标记为BadExpr并记录诊断信息
+func diagnose(parser IParser, origin string) {
+	var d = &diagnoser{origin: origin}
+	switch x := parser.(type) {
+	case *Partial:
+		if x.Statement != nil {
+			diagnose(x.Statement, origin)
+		}
+		return
+	case *Select:
+		d.checkSelect(x)
+		x.diagnostics = d.diagnostics
+	case *Update:
+		for _, field := range x.Fields {
+			d.checkField(field)
+		}
+		d.checkConditions(x.Where)
+		x.diagnostics = d.diagnostics
+	case *Delete:
+		d.checkConditions(x.Where)
+		x.diagnostics = d.diagnostics
+	case *Insert:
+		if x.Query != nil {
+			d.checkSelect(x.Query)
+		}
+		x.diagnostics = d.diagnostics
+	}
+}
+
+// 语法树检查器
+type diagnoser struct {
+	origin      string        // 原始sql
+	diagnostics []*Diagnostic // 诊断信息
+}
+
+// 记录无法解析的表达式，已定位时使用原始sql中的原文
+func (d *diagnoser) report(text, message string, span Span) *BadExpr {
+	if span.IsValid() {
+		text = span.Text(d.origin)
+	}
+	d.diagnostics = append(d.diagnostics, &Diagnostic{Message: message, Text: text, Span: span})
+	return &BadExpr{Text: text, Message: message}
+}
+
+func (d *diagnoser) checkSelect(x *Select) {
+	if x == nil {
+		return
+	}
+	for _, field := range x.Fields {
+		d.checkField(field)
+	}
+	if x.Table != nil {
+		d.checkSelect(x.Table.Select)
+	}
+	for _, join := range x.Joins {
+		if join.Table != nil {
+			d.checkSelect(join.Table.Select)
+		}
+	}
+	for _, conditions := range [][]*Condition{x.Prewhere, x.Where, x.StartWith, x.ConnectBy, x.Having} {
+		d.checkConditions(conditions)
+	}
+	for _, operation := range x.SetOperations {
+		d.checkSelect(operation.Select)
+	}
+}
+
+func (d *diagnoser) checkField(f *Field) {
+	switch text := strings.TrimSpace(f.Name + consts.Blank + f.Value); {
+	case f.Name == consts.Empty || strings.HasSuffix(f.Name, consts.Comma):
+		f.Bad = d.report(text, "字段为空", f.Span)
+	case !balanced(f.Name) || !balanced(f.Value):
+		f.Bad = d.report(text, "字段括号不匹配", f.Span)
+	}
+}
+
+func (d *diagnoser) checkConditions(conditions []*Condition) {
+	for _, c := range conditions {
+		if len(c.Conditions) > 0 {
+			d.checkConditions(c.Conditions)
+			continue
+		}
+		d.checkSelect(c.Select)
+		var text = strings.TrimSpace(strings.Join(strings.Fields(c.Name+consts.Blank+c.Operator+consts.Blank+c.Value), consts.Blank))
+		switch {
+		case c.Name == consts.Empty:
+			c.Bad = d.report(text, "条件为空", c.Span)
+		case c.Operator != consts.Empty && c.Value == consts.Empty && len(c.Values) == 0 && c.Select == nil:
+			c.Bad = d.report(text, "条件缺少值", c.Span)
+		case !balanced(c.Name) || !balanced(c.Value):
+			c.Bad = d.report(text, "条件括号不匹配", c.Span)
+		case danglingWords[strings.ToLower(text[strings.LastIndex(text, consts.Blank)+1:])]:
+			c.Bad = d.report(text, "条件不完整", c.Span)
+		}
+	}
+}
+
+// 不能位于条件末尾的运算符以及逻辑关键字
+var danglingWords = map[string]bool{
+	"=": true, "!=": true, "<>": true, "<": true, ">": true, "<=": true, ">=": true, consts.LIKE: true, consts.IN: true, consts.IS: true,
+	consts.NOT: true, consts.AND: true, consts.OR: true, "between": true,
+}
+
+// 括号是否匹配，忽略引号内的内容
+func balanced(sql string) bool {
+	var depth int
+	for i := 0; i < len(sql); i++ {
+		if end := utils.QuoteEnd(sql, i); end > i {
+			i = end
+		} else if sql[i] == '(' {
+			depth++
+		} else if sql[i] == ')' {
+			if depth--; depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
@@ -0,0 +1,786 @@
+package beautify
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+	"github.com/go-xuan/sqlx/utils"
+)
+
+var (
+	callPattern        = regexp.MustCompile(`(?i)\b([a-z_][a-z0-9_]*)\s*\(`)                                  // 函数调用：ifnull(
+	withinGroupPattern = regexp.MustCompile(`(?i)^\s*within\s+group\s*\(`)                                    // listagg(a, ',') within group (order by a)
+	nowPattern         = regexp.MustCompile(`(?i)\b(now\s*\(\s*\)|getdate\s*\(\s*\)|sysdate\b(\s*\(\s*\))?)`) // 当前时间
+	booleanPattern     = regexp.MustCompile(`(?i)\b(true|false)\b`)                                           // 布尔字面量
+	isPattern          = regexp.MustCompile(`(?i)\bis\s+(not\s+)?$`)                                          // a is true
+	rownumPattern      = regexp.MustCompile(`(?i)\brownum\b`)                                                 // oracle：rownum
+	excludedPattern    = regexp.MustCompile(`(?i)\bexcluded\s*\.\s*([^\s,()]+)`)                              // postgresql：excluded.a
+	valuesCallPattern  = regexp.MustCompile(`(?i)\bvalues\s*\(\s*([^\s,()]+)\s*\)`)                           // mysql：values(a)
+)
+
+// 空值替换函数
+var nullFunctions = map[Dialect]string{
+	MySQL: "ifnull", PostgreSQL: "coalesce", SQLite: "ifnull", Oracle: "nvl", SQLServer: "isnull", Hive: "nvl", ClickHouse: "ifNull",
+}
+
+// 当前时间函数
+var nowFunctions = map[Dialect]string{
+	MySQL: "now()", PostgreSQL: "now()", SQLite: "datetime('now')", Oracle: "sysdate", SQLServer: "getdate()", Hive: "current_timestamp()", ClickHouse: "now()",
+}
+
+// 日期格式化函数
+type dateFormatter struct {
+	function string // 函数名
+	swap     bool   // 格式参数是否在日期参数之前（sqlite：strftime('%Y', d)）
+	family   int    // 格式符族，对应dateTokens的列
+}
+
+// 日期格式符族
+const (
+	mysqlTokens = iota
+	oracleTokens
+	javaTokens
+	sqliteTokens
+	clickhouseTokens
+)
+
+var dateFormatters = map[Dialect]*dateFormatter{
+	MySQL:      {function: "date_format", family: mysqlTokens},
+	PostgreSQL: {function: "to_char", family: oracleTokens},
+	Oracle:     {function: "to_char", family: oracleTokens},
+	SQLServer:  {function: "format", family: javaTokens},
+	Hive:       {function: "date_format", family: javaTokens},
+	SQLite:     {function: "strftime", swap: true, family: sqliteTokens},
+	ClickHouse: {function: "formatDateTime", family: clickhouseTokens},
+}
+
+// 日期格式符对照表，列依次为mysql、oracle/postgresql、java（hive、sql server）、sqlite、clickhouse，为空表示不支持
+var dateTokens = [][5]string{
+	{"%Y", "YYYY", "yyyy", "%Y", "%Y"},
+	{"%y", "YY", "yy", "", "%y"},
+	{"%m", "MM", "MM", "%m", "%m"},
+	{"%d", "DD", "dd", "%d", "%d"},
+	{"%H", "HH24", "HH", "%H", "%H"},
+	{"%h", "HH12", "hh", "", "%I"},
+	{"%i", "MI", "mm", "%M", "%i"},
+	{"%s", "SS", "ss", "%S", "%S"},
+	{"%f", "US", "SSSSSS", "", "%f"},
+	{"%p", "AM", "a", "", "%p"},
+	{"%b", "Mon", "MMM", "", "%b"},
+	{"%M", "Month", "MMMM", "", ""},
+	{"%a", "Dy", "EEE", "", "%a"},
+	{"%W", "Day", "EEEE", "", ""},
+	{"%j", "DDD", "DDD", "%j", "%j"},
+}
+
+// Untranslated 无法转换的语法
+type Untranslated struct {
+	Construct string // 语法片段
+	Reason    string // 原因
+}
+
+// Transpile 将sql从源方言转换为目标方言，改写标识符引号、分页、常用函数、冲突更新以及布尔字面量，
+// 返回美化后的目标sql以及所有无法转换的语法；源方言为nil时根据语法特征推断
+func Transpile(sql string, from, to Dialect) (string, []*Untranslated) {
+	if from == nil {
+		if from, _ = DetectDialect(sql); from == nil {
+			from = to
+		}
+	}
+	var t = &transpiler{from: from, to: to, values: make(map[string]string)}
+	parser := Parse(t.rewriteText(strings.TrimSpace(sql)), WithDialect(from))
+	t.rewriteStatement(parser)
+	if base, ok := parser.(interface{ setDialect(Dialect) }); ok {
+		base.setDialect(to)
+	}
+	return parser.Beautify(), t.untranslated
+}
+
+// TranspileStatement 将解析后的语句从源方言转换为目标方言，转换的是语句原文（Parse解析时传入的sql），
+// 源方言为nil时取语句的方言
+func TranspileStatement(stmt IParser, from, to Dialect) (string, []*Untranslated) {
+	if from == nil {
+		from = stmt.Dialect()
+	}
+	var sql string
+	if x, ok := stmt.(interface{ text() string }); ok {
+		sql = x.text()
+	}
+	return Transpile(sql, from, to)
+}
+
+// 方言转换器
+type transpiler struct {
+	from, to     Dialect
+	values       map[string]string // 屏蔽的字符串字面量以及引用标识符（占位 -> 原文）
+	untranslated []*Untranslated   // 无法转换的语法
+}
+
+// 记录无法转换的语法
+func (t *transpiler) report(construct, reason string) {
+	t.untranslated = append(t.untranslated, &Untranslated{Construct: construct, Reason: reason})
+}
+
+// 记录目标方言不支持的子句
+func (t *transpiler) reportClause(construct string, clause Clause) {
+	if !t.to.Supports(clause) {
+		t.report(construct, t.to.Name()+"不支持"+string(clause))
+	}
+}
+
+// 改写sql文本：屏蔽并转换字符串字面量以及引用标识符之后，改写函数、当前时间以及布尔字面量
+func (t *transpiler) rewriteText(sql string) string {
+	var builder = strings.Builder{}
+	var offset, oldnew = 0, []string(nil)
+	for i := 0; i < len(sql); i++ {
+		if end := utils.QuoteEnd(sql, i); end > i {
+			key := consts.ReplacePrefix + strconv.Itoa(len(oldnew)/2+1) + consts.ReplaceSuffix
+			value := t.rewriteQuoted(sql[i : end+1])
+			builder.WriteString(sql[offset:i])
+			builder.WriteString(key)
+			oldnew = append(oldnew, key, value)
+			t.values[key] = value
+			offset, i = end+1, end
+		}
+	}
+	builder.WriteString(sql[offset:])
+	sql = builder.String()
+	if rownumPattern.MatchString(sql) && !t.to.Supports(RownumClause) {
+		t.report("rownum", t.to.Name()+"不支持rownum，请改用分页子句")
+	}
+	sql = t.rewriteCalls(sql)
+	sql = t.rewriteNow(sql)
+	sql = t.rewriteBooleans(sql)
+	if len(oldnew) > 0 {
+		sql = strings.NewReplacer(oldnew...).Replace(sql)
+	}
+	return sql
+}
+
+// 转换字符串字面量以及引用标识符
+func (t *transpiler) rewriteQuoted(token string) string {
+	switch quote := token[:1]; {
+	case quote == "'":
+		return t.rewriteString(token)
+	case contains(t.from.Quotes(), quote):
+		if contains(t.to.Quotes(), quote) {
+			return token
+		}
+		name := token[1 : len(token)-1]
+		if quote == "[" {
+			name = strings.ReplaceAll(name, "]]", "]")
+		} else {
+			name = strings.ReplaceAll(name, quote+quote, quote)
+		}
+		return t.to.QuoteIdentifier(name)
+	case quote == `"`: // 源方言不以双引号引用标识符时（mysql、hive）为字符串
+		return t.rewriteString("'" + strings.ReplaceAll(token[1:len(token)-1], `""`, `"`) + "'")
+	default:
+		return token
+	}
+}
+
+// 转换字符串字面量的转义规则
+func (t *transpiler) rewriteString(token string) string {
+	if t.from == t.to {
+		return token
+	}
+	value := t.from.UnescapeString(token)
+	if strings.ContainsAny(value, "\n\r\t\x00") {
+		t.report(token, "字符串包含控制字符，无法转换转义规则")
+		return token
+	}
+	return t.to.EscapeString(value)
+}
+
+// 改写函数调用，嵌套函数由内向外改写
+func (t *transpiler) rewriteCalls(sql string) string {
+	var builder = strings.Builder{}
+	for {
+		match := callPattern.FindStringSubmatchIndex(sql)
+		if match == nil {
+			break
+		}
+		open := match[1] - 1
+		end := closingBracket(sql, open)
+		if end < 0 {
+			break
+		}
+		name := sql[match[2]:match[3]]
+		args := t.rewriteCalls(sql[open+1 : end])
+		builder.WriteString(sql[:match[0]])
+		if call, consumed, ok := t.rewriteCall(strings.ToLower(name), args, sql[end+1:]); ok {
+			builder.WriteString(call)
+			sql = sql[end+1+consumed:]
+		} else {
+			builder.WriteString(sql[match[0] : open+1])
+			builder.WriteString(args)
+			builder.WriteString(consts.RightBracket)
+			sql = sql[end+1:]
+		}
+	}
+	builder.WriteString(sql)
+	return builder.String()
+}
+
+// 改写单个函数调用，rest为函数调用之后的sql，返回改写结果以及额外消耗的rest长度
+func (t *transpiler) rewriteCall(name, args, rest string) (string, int, bool) {
+	list, last := utils.SplitExcludeInBracket(args, consts.Comma)
+	list = append(list, last)
+	for i := range list {
+		list[i] = strings.TrimSpace(list[i])
+	}
+	switch {
+	case strings.EqualFold(name, nullFunctions[t.from]) && name != "coalesce" && nullFunctions[t.to] != consts.Empty && len(list) == 2:
+		return nullFunctions[t.to] + consts.LeftBracket + strings.Join(list, ", ") + consts.RightBracket, 0, true
+	case dateFormatters[t.from] != nil && strings.EqualFold(name, dateFormatters[t.from].function) && len(list) == 2:
+		call, ok := t.rewriteDateFormat(name, list)
+		return call, 0, ok
+	case name == "group_concat" || name == "string_agg" || name == "listagg":
+		var within string
+		var consumed int
+		if match := withinGroupPattern.FindStringIndex(rest); match != nil {
+			if end := closingBracket(rest, match[1]-1); end > 0 {
+				within, consumed = strings.TrimSpace(rest[match[1]:end]), end+1
+			}
+		}
+		call, ok := t.rewriteStringAgg(name, args, within)
+		if !ok {
+			consumed = 0
+		}
+		return call, consumed, ok
+	}
+	return consts.Empty, 0, false
+}
+
+// 改写日期格式化函数
+func (t *transpiler) rewriteDateFormat(name string, args []string) (string, bool) {
+	source, target := dateFormatters[t.from], dateFormatters[t.to]
+	if target == nil || source == target {
+		return consts.Empty, false
+	}
+	date, format := args[0], args[1]
+	if source.swap {
+		date, format = format, date
+	}
+	literal, ok := t.values[format]
+	if !ok {
+		t.report(name+"("+strings.Join(args, ", ")+")", "日期格式不是字符串字面量，无法转换")
+		return consts.Empty, false
+	}
+	converted, missing := convertDateFormat(t.from.UnescapeString(literal), source.family, target.family)
+	if len(missing) > 0 {
+		t.report(literal, t.to.Name()+"不支持日期格式符"+strings.Join(missing, "、"))
+	}
+	format = t.to.EscapeString(converted)
+	if target.swap {
+		date, format = format, date
+	}
+	return target.function + consts.LeftBracket + date + ", " + format + consts.RightBracket, true
+}
+
+// 转换日期格式符，返回转换后的格式以及目标不支持的格式符
+func convertDateFormat(format string, from, to int) (string, []string) {
+	var builder = strings.Builder{}
+	var missing []string
+	for i := 0; i < len(format); {
+		var hit = -1
+		for j, tokens := range dateTokens {
+			token := tokens[from]
+			if token == consts.Empty || len(format)-i < len(token) {
+				continue
+			}
+			// oracle格式符忽略大小写，其余区分大小写；优先匹配较长的格式符（MMMM优先于MM）
+			if from == oracleTokens && strings.EqualFold(format[i:i+len(token)], token) || format[i:i+len(token)] == token {
+				if hit < 0 || len(token) > len(dateTokens[hit][from]) {
+					hit = j
+				}
+			}
+		}
+		if hit < 0 {
+			builder.WriteByte(format[i])
+			i++
+			continue
+		}
+		if token := dateTokens[hit][to]; token != consts.Empty {
+			builder.WriteString(token)
+		} else {
+			missing = append(missing, dateTokens[hit][from])
+			builder.WriteString(dateTokens[hit][from])
+		}
+		i += len(dateTokens[hit][from])
+	}
+	return builder.String(), missing
+}
+
+// 字符串聚合
+type stringAgg struct {
+	distinct  bool   // 是否去重
+	expr      string // 聚合表达式
+	separator string // 分隔符
+	order     string // 排序条件（不包含order by）
+}
+
+// 改写字符串聚合函数（group_concat、string_agg、listagg）
+func (t *transpiler) rewriteStringAgg(name, args, within string) (string, bool) {
+	var agg = &stringAgg{separator: "','"}
+	if lower := strings.ToLower(args); strings.HasPrefix(lower, consts.DISTINCT+consts.Blank) {
+		agg.distinct, args = true, strings.TrimSpace(args[9:])
+	}
+	if name == "group_concat" && t.from == MySQL {
+		lower := strings.ToLower(args)
+		if index := utils.IndexExcludeBrackets(lower, "separator", true); index > 0 {
+			agg.separator, args = strings.TrimSpace(args[index+9:]), args[:index]
+			lower = lower[:index]
+		}
+		if index := utils.IndexExcludeBrackets(lower, consts.ORDERBY, true); index > 0 {
+			agg.order, args = strings.TrimSpace(args[index+8:]), args[:index]
+		}
+		agg.expr = strings.TrimSpace(args)
+	} else {
+		list, last := utils.SplitExcludeInBracket(args, consts.Comma)
+		if len(list) != 1 {
+			return consts.Empty, false
+		}
+		agg.expr, agg.separator = strings.TrimSpace(list[0]), strings.TrimSpace(last)
+		if index := utils.IndexExcludeBrackets(strings.ToLower(agg.separator), consts.ORDERBY, true); index > 0 {
+			agg.order, agg.separator = strings.TrimSpace(agg.separator[index+8:]), strings.TrimSpace(agg.separator[:index])
+		}
+	}
+	if lower := strings.ToLower(within); strings.HasPrefix(lower, consts.ORDERBY+consts.Blank) {
+		agg.order = strings.TrimSpace(within[9:])
+	}
+	if index := utils.IndexExcludeBrackets(agg.expr, consts.Comma, false); index >= 0 && t.to != MySQL {
+		t.report(name+"("+args+")", t.to.Name()+"字符串聚合仅支持单个表达式")
+	}
+	return t.buildStringAgg(agg), true
+}
+
+// 构建目标方言的字符串聚合函数
+func (t *transpiler) buildStringAgg(agg *stringAgg) string {
+	var distinct string
+	if agg.distinct {
+		distinct = consts.DISTINCT + consts.Blank
+	}
+	var order string
+	if agg.order != consts.Empty {
+		order = " order by " + agg.order
+	}
+	var construct = func() string {
+		return "string aggregation (" + agg.expr + ")"
+	}
+	switch t.to {
+	case MySQL:
+		var separator string
+		if agg.separator != "','" {
+			separator = " separator " + agg.separator
+		}
+		return "group_concat(" + distinct + agg.expr + order + separator + ")"
+	case SQLite:
+		if order != consts.Empty {
+			t.report(construct(), "sqlite字符串聚合不支持排序")
+		}
+		if agg.distinct {
+			return "group_concat(" + distinct + agg.expr + ")"
+		}
+		return "group_concat(" + agg.expr + ", " + agg.separator + ")"
+	case SQLServer:
+		if agg.distinct {
+			t.report(construct(), "sqlserver字符串聚合不支持distinct")
+		}
+		if order != consts.Empty {
+			return "string_agg(" + agg.expr + ", " + agg.separator + ") within group (" + strings.TrimSpace(order) + ")"
+		}
+		return "string_agg(" + agg.expr + ", " + agg.separator + ")"
+	case Oracle:
+		if order != consts.Empty {
+			return "listagg(" + distinct + agg.expr + ", " + agg.separator + ") within group (" + strings.TrimSpace(order) + ")"
+		}
+		return "listagg(" + distinct + agg.expr + ", " + agg.separator + ")"
+	case Hive, ClickHouse:
+		if order != consts.Empty {
+			t.report(construct(), t.to.Name()+"字符串聚合不支持排序")
+		}
+		if t.to == Hive {
+			if agg.distinct {
+				return "concat_ws(" + agg.separator + ", collect_set(" + agg.expr + "))"
+			}
+			return "concat_ws(" + agg.separator + ", collect_list(" + agg.expr + "))"
+		}
+		if agg.distinct {
+			return "arrayStringConcat(groupUniqArray(" + agg.expr + "), " + agg.separator + ")"
+		}
+		return "arrayStringConcat(groupArray(" + agg.expr + "), " + agg.separator + ")"
+	default:
+		return "string_agg(" + distinct + agg.expr + ", " + agg.separator + order + ")"
+	}
+}
+
+// 改写当前时间函数（now()、getdate()、sysdate）
+func (t *transpiler) rewriteNow(sql string) string {
+	if t.from == t.to || nowFunctions[t.to] == consts.Empty {
+		return sql
+	}
+	return nowPattern.ReplaceAllStringFunc(sql, func(string) string {
+		return nowFunctions[t.to]
+	})
+}
+
+// 目标方言不支持布尔字面量时改写为1、0
+func (t *transpiler) rewriteBooleans(sql string) string {
+	if t.to.Supports(BooleanClause) || !t.from.Supports(BooleanClause) {
+		return sql
+	}
+	var builder = strings.Builder{}
+	var offset int
+	for _, match := range booleanPattern.FindAllStringIndex(sql, -1) {
+		word := sql[match[0]:match[1]]
+		if isPattern.MatchString(sql[:match[0]]) {
+			t.report("is "+word, t.to.Name()+"不支持布尔字面量")
+			continue
+		}
+		builder.WriteString(sql[offset:match[0]])
+		if strings.EqualFold(word, "true") {
+			builder.WriteString("1")
+		} else {
+			builder.WriteString("0")
+		}
+		offset = match[1]
+	}
+	builder.WriteString(sql[offset:])
+	return builder.String()
+}
+
+// 改写语句结构：分页、集合运算、冲突更新以及方言特有子句
+func (t *transpiler) rewriteStatement(parser IParser) {
+	switch x := parser.(type) {
+	case *Select:
+		t.rewriteSelect(x)
+	case *Insert:
+		t.rewriteUpsert(x)
+		t.rewriteOutputs(x.Output, x.Returning)
+		if x.Query != nil {
+			t.rewriteSelect(x.Query)
+		}
+		for _, values := range x.ValueData {
+			t.checkSubqueries(values...)
+		}
+	case *Update:
+		t.rewriteOutputs(x.Output, x.Returning)
+		t.checkOrderByLimit(consts.UPDATE, x.OrderBy, x.Limit)
+		for _, field := range x.Fields {
+			t.checkSubqueries(field.Value)
+		}
+		t.rewriteConditions(x.Where)
+	case *Delete:
+		t.rewriteOutputs(x.Output, x.Returning)
+		t.checkOrderByLimit(consts.DELETE, x.OrderBy, x.Limit)
+		t.rewriteConditions(x.Where)
+	}
+}
+
+// 检查更新、删除语句的order by以及limit，仅mysql支持
+func (t *transpiler) checkOrderByLimit(keyword string, orderBy []string, limit string) {
+	if t.to == MySQL {
+		return
+	}
+	if len(orderBy) > 0 {
+		t.report("order by "+strings.Join(orderBy, ", "), t.to.Name()+"不支持"+keyword+"语句的order by")
+	}
+	if limit != consts.Empty {
+		t.report("limit "+limit, t.to.Name()+"不支持"+keyword+"语句的limit")
+	}
+}
+
+// 检查output、returning子句
+func (t *transpiler) rewriteOutputs(output, returning *Output) {
+	if output != nil {
+		t.reportClause(output.beautify(), OutputClause)
+	}
+	if returning != nil {
+		t.reportClause(returning.beautify(), ReturningClause)
+	}
+}
+
+// 改写查询，包括子查询以及集合运算中的查询
+func (t *transpiler) rewriteSelect(x *Select) {
+	t.checkSubqueries(x.OrderBy...) // 先于分页改写检查，sql server分页补充的order by (select null)无需检查
+	t.rewritePagination(x)
+	t.checkSelectClauses(x)
+	for _, operation := range x.SetOperations {
+		if operation.Operator == "minus" && !t.to.Supports(SetMinusClause) {
+			operation.Operator = "except"
+		} else if operation.Operator == "except" && t.to.Supports(SetMinusClause) {
+			operation.Operator = "minus"
+		}
+		t.rewriteSelect(operation.Select)
+	}
+	var tables = []*Table{x.Table}
+	for _, join := range x.Joins {
+		tables = append(tables, join.Table)
+	}
+	for _, table := range tables {
+		if table != nil && table.Select != nil {
+			t.rewriteSelect(table.Select)
+		}
+	}
+	for _, field := range x.Fields {
+		t.checkSubqueries(field.Name)
+	}
+	for _, join := range x.Joins {
+		t.checkSubqueries(join.On)
+	}
+	t.checkSubqueries(x.GroupBy...)
+	t.rewriteConditions(x.Prewhere)
+	t.rewriteConditions(x.Where)
+	t.rewriteConditions(x.Having)
+}
+
+// 改写条件中的子查询
+func (t *transpiler) rewriteConditions(conditions []*Condition) {
+	for _, condition := range conditions {
+		if condition.Select != nil {
+			t.rewriteSelect(condition.Select)
+		}
+		t.checkSubqueries(condition.Name, condition.Value)
+		t.rewriteConditions(condition.Conditions)
+	}
+}
+
+// 检查表达式中的子查询（查询字段、关联条件、标量子查询等），表达式以原文输出，
+// 其中的函数、引号已在改写sql文本时转换，分页以及方言子句无法改写，只能报告
+func (t *transpiler) checkSubqueries(exprs ...string) {
+	for _, expr := range exprs {
+		lower := strings.ToLower(expr)
+		for i := 0; i < len(expr); i++ {
+			index := strings.Index(lower[i:], "(select ")
+			if index < 0 {
+				break
+			}
+			open := i + index
+			end := closingBracket(expr, open)
+			if end < 0 {
+				break
+			}
+			t.checkSubquery(expr[open+1 : end])
+			i = end
+		}
+	}
+}
+
+// 检查单个表达式子查询，需要改写或者包含目标方言不支持的语法时报告
+func (t *transpiler) checkSubquery(sql string) {
+	var sub = &transpiler{from: t.from, to: t.to, values: t.values}
+	query, err := recoverParse(sql, t.from)
+	if err != nil {
+		t.report(sql, "无法解析子查询")
+		return
+	}
+	if x, ok := query.(*Select); ok {
+		before := x.Beautify()
+		if sub.rewriteSelect(x); x.Beautify() != before {
+			t.report(sql, "子查询位于表达式中，其分页等语法无法转换为"+t.to.Name())
+		}
+	}
+	t.untranslated = append(t.untranslated, sub.untranslated...)
+}
+
+// 检查查询中目标方言不支持的子句
+func (t *transpiler) checkSelectClauses(x *Select) {
+	if len(x.Modifiers) > 0 && t.to != MySQL {
+		t.report(strings.Join(x.Modifiers, consts.Blank), t.to.Name()+"不支持mysql查询修饰符")
+	}
+	if len(x.DistinctOn) > 0 {
+		t.reportClause("distinct "+x.beautifyDistinctOn(), DistinctOnClause)
+	}
+	var tables = []*Table{x.Table}
+	for _, join := range x.Joins {
+		tables = append(tables, join.Table)
+		if isApply(join.Type) {
+			t.reportClause(join.Type, ApplyClause)
+		}
+	}
+	for _, table := range tables {
+		if table == nil {
+			continue
+		}
+		if len(table.Hints) > 0 && (t.from != t.to || !t.to.Supports(TableHintClause)) {
+			t.report(strings.Join(table.Hints, consts.Blank), t.to.Name()+"不支持"+t.from.Name()+"表提示")
+		}
+		if table.Final {
+			t.reportClause("final", FinalClause)
+		}
+		if table.Sample != consts.Empty && t.from != t.to {
+			t.report(table.Sample, t.to.Name()+"不支持"+t.from.Name()+"表采样")
+		}
+	}
+	if len(x.LateralViews) > 0 {
+		t.reportClause(x.LateralViews[0].beautify(), LateralViewClause)
+	}
+	if len(x.ClusterBy)+len(x.DistributeBy)+len(x.SortBy) > 0 {
+		t.reportClause(strings.TrimSpace(x.beautifyDistributions()), DistributeClause)
+	}
+	if len(x.ConnectBy) > 0 {
+		t.reportClause("connect by", ConnectByClause)
+	}
+	if len(x.ArrayJoins) > 0 {
+		t.reportClause(x.ArrayJoins[0].beautify(), ArrayJoinClause)
+	}
+	if len(x.Prewhere) > 0 {
+		t.reportClause("prewhere", PrewhereClause)
+	}
+	if x.LimitBy != nil {
+		t.reportClause("limit "+x.LimitBy.Limit+" by "+strings.Join(x.LimitBy.Columns, ", "), LimitByClause)
+	}
+	if len(x.Settings) > 0 {
+		t.reportClause("settings", SettingsClause)
+	}
+	if x.Format != consts.Empty {
+		t.reportClause("format "+x.Format, FormatClause)
+	}
+}
+
+// 改写分页：limit o, n、limit n offset o、offset o rows fetch next n rows only、top n
+func (t *transpiler) rewritePagination(x *Select) {
+	count, offset, ok := t.pagination(x)
+	if !ok || count == consts.Empty && offset == consts.Empty {
+		return
+	}
+	x.Limit, x.Offset, x.Fetch, x.Top = consts.Empty, consts.Empty, consts.Empty, nil
+	switch t.to.Pagination() {
+	case LimitCommaPagination:
+		if offset == consts.Empty {
+			x.Limit = count
+		} else if count == consts.Empty {
+			x.Limit = offset + ", 18446744073709551615" // mysql仅偏移时需要指定最大取数
+		} else {
+			x.Limit = offset + ", " + count
+		}
+	case LimitOffsetPagination:
+		if count == consts.Empty {
+			x.Offset = offset
+		} else if x.Limit = count; offset != consts.Empty {
+			x.Limit += " offset " + offset
+		}
+	default:
+		if offset == consts.Empty && t.to.Supports(TopClause) {
+			x.Top = &Top{Count: count}
+			return
+		}
+		if offset != consts.Empty {
+			x.Offset = offset + " rows"
+		}
+		if count != consts.Empty && offset != consts.Empty {
+			x.Fetch = "next " + count + " rows only"
+		} else if count != consts.Empty {
+			x.Fetch = "first " + count + " rows only"
+		}
+		if t.to.Supports(TopClause) && len(x.OrderBy) == 0 { // sql server：offset/fetch必须搭配order by
+			x.OrderBy = []string{"(select null)"}
+		}
+	}
+}
+
+// 获取查询的取数以及偏移，无法转换时返回false
+func (t *transpiler) pagination(x *Select) (count, offset string, ok bool) {
+	if top := x.Top; top != nil {
+		if top.Percent || top.WithTies {
+			t.report(top.beautify(), "top percent、with ties无法转换为分页子句")
+			return consts.Empty, consts.Empty, false
+		}
+		count = utils.TrimBrackets(top.Count)
+	}
+	if limit := strings.TrimSpace(x.Limit); limit != consts.Empty {
+		if index := strings.Index(strings.ToLower(limit), " offset "); index > 0 {
+			count, offset = strings.TrimSpace(limit[:index]), strings.TrimSpace(limit[index+8:])
+		} else if before, after := utils.CutString(limit, consts.Comma); after != consts.Empty {
+			offset, count = strings.TrimSpace(before), strings.TrimSpace(after)
+		} else {
+			count = limit
+		}
+	}
+	if x.Offset != consts.Empty {
+		offset = strings.Fields(x.Offset)[0]
+	}
+	if words := strings.Fields(x.Fetch); len(words) >= 2 {
+		if strings.HasSuffix(x.Fetch, "with ties") || strings.HasSuffix(x.Fetch, "percent") {
+			t.report("fetch "+x.Fetch, "fetch with ties、percent无法转换为分页子句")
+			return consts.Empty, consts.Empty, false
+		}
+		count = words[1]
+	}
+	return count, offset, true
+}
+
+// 改写冲突更新：on duplicate key update <-> on conflict，insert ignore <-> on conflict do nothing
+func (t *transpiler) rewriteUpsert(x *Insert) {
+	var upsert = x.Upsert
+	if upsert == nil && !x.Ignore {
+		return
+	}
+	switch {
+	case t.to.Supports(OnDuplicateClause):
+		if upsert == nil || upsert.Keyword == onDuplicateKey {
+			return
+		}
+		if upsert.Nothing {
+			x.Ignore, x.Upsert = true, nil
+			return
+		}
+		if upsert.Where != consts.Empty {
+			t.report("where "+upsert.Where, t.to.Name()+"冲突更新不支持where条件")
+		}
+		upsert.Keyword, upsert.Conflict, upsert.Where = onDuplicateKey, nil, consts.Empty
+		for _, field := range upsert.Fields {
+			field.Value = excludedPattern.ReplaceAllString(field.Value, "values($1)")
+		}
+	case t.to.Supports(OnConflictClause):
+		if x.Ignore {
+			x.Ignore, x.Upsert = false, &Upsert{Keyword: onConflict, Nothing: true}
+			return
+		}
+		if upsert.Keyword == onConflict {
+			return
+		}
+		upsert.Keyword = onConflict
+		t.report(onDuplicateKey, t.to.Name()+"需要在on conflict之后指定冲突字段")
+		for _, field := range upsert.Fields {
+			field.Value = valuesCallPattern.ReplaceAllString(field.Value, "excluded.$1")
+		}
+	default:
+		if x.Ignore {
+			t.report("insert ignore", t.to.Name()+"不支持insert ignore，请改用merge")
+		}
+		if upsert != nil {
+			t.report(upsert.Keyword, t.to.Name()+"不支持冲突更新，请改用merge")
+		}
+	}
+}
+
+// 获取从open处左括号开始的闭合右括号下标
+func closingBracket(sql string, open int) int {
+	var depth int
+	for i := open; i < len(sql); i++ {
+		switch sql[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// 切片是否包含字符串
+func contains(list []string, str string) bool {
+	for _, item := range list {
+		if item == str {
+			return true
+		}
+	}
+	return false
+}
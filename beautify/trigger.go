@@ -0,0 +1,203 @@
+package beautify
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+	"github.com/go-xuan/sqlx/utils"
+)
+
+// ParseCreateTriggerSQL 解析创建触发器SQL
+func ParseCreateTriggerSQL(sql string, indent ...int) *CreateTrigger {
+	return parseCreateTrigger(sql, nil, indent...)
+}
+
+// 按方言解析创建触发器SQL，未指定方言时为nil
+func parseCreateTrigger(sql string, dialect Dialect, indent ...int) *CreateTrigger {
+	// sql初始化
+	var parser = &CreateTrigger{
+		Base: NewBase(strings.TrimSpace(sql), indent...),
+	}
+	parser.dialect = dialect
+
+	// sql解析
+	parser.parseBody()    // 解析触发器动作，需要在解析准备之前提取以保留原文
+	parser.parsePrepare() // 解析准备
+	parser.parseHeader()  // 解析触发器头
+	parser.parseFinish()  // 解析完成
+
+	return parser
+}
+
+type CreateTrigger struct {
+	Base
+	OrReplace  bool         // 是否or replace
+	Definer    string       // 定义者（mysql：definer=`root`@`%`）
+	Name       string       // 触发器名
+	Timing     string       // 触发时机（before、after、instead of）
+	Events     []string     // 触发事件（insert、update of a, b、delete、truncate）
+	Table      *Table       // 触发表
+	ForEach    string       // 触发粒度（row、statement）
+	When       string       // 触发条件
+	Options    []string     // 其他选项原文（referencing、from、deferrable等）
+	Order      string       // 触发顺序（mysql：follows x、precedes x）
+	Body       *RoutineBody // 触发器动作（begin...end、单条语句、execute function f()）
+	tableFirst bool         // sql server语法：on t在触发时机之前，触发事件以逗号分隔
+}
+
+// Beautify SQL美化输出
+func (x *CreateTrigger) Beautify() string {
+	var sql = strings.Builder{}
+	sql.WriteString(consts.CREATE)
+	if x.OrReplace {
+		sql.WriteString(" or replace")
+	}
+	if x.Definer != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(x.Definer)
+	}
+	sql.WriteString(consts.Blank)
+	sql.WriteString(consts.TRIGGER)
+	sql.WriteString(consts.Blank)
+	sql.WriteString(x.Name)
+	sql.WriteString(consts.NextLine)
+	if x.tableFirst { // sql server：on t [with ...] after insert, update
+		sql.WriteString(consts.ON)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(x.Table.beautify())
+		for _, option := range x.Options {
+			sql.WriteString(consts.NextLine)
+			sql.WriteString(option)
+		}
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(x.Timing)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(strings.Join(x.Events, ", "))
+	} else { // postgresql、oracle、mysql：after insert or update on t
+		sql.WriteString(x.Timing)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(strings.Join(x.Events, " or "))
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.ON)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(x.Table.beautify())
+		for _, option := range x.Options {
+			sql.WriteString(consts.NextLine)
+			sql.WriteString(option)
+		}
+	}
+	if x.ForEach != consts.Empty {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString("for each ")
+		sql.WriteString(x.ForEach)
+	}
+	if x.Order != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(x.Order)
+	}
+	if x.When != consts.Empty {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(consts.WHEN)
+		sql.WriteString(" (")
+		sql.WriteString(x.When)
+		sql.WriteString(consts.RightBracket)
+	}
+	return x.finish(sql.String(), x.Body.beautify())
+}
+
+// 提取触发器动作，保留动作原文
+func (x *CreateTrigger) parseBody() *CreateTrigger {
+	sql := x.tempSql
+	var body = &RoutineBody{dialect: x.dialect}
+	var start = -1
+	if match := regexp.MustCompile(`(?is)\bexecute\s+(function|procedure)\b`).FindStringIndex(sql); match != nil {
+		start = match[0] // postgresql：execute function f()
+	} else if match = regexp.MustCompile(`(?is)\bfor\s+each\s+row(\s+(follows|precedes)\s+\S+)?`).FindStringIndex(sql); match != nil {
+		start = match[1] // mysql：for each row [follows x] 之后为触发器动作
+	} else if match = regexp.MustCompile(`(?is)\sas\s`).FindStringIndex(sql); match != nil {
+		body.as, start = true, match[1] // sql server：as 之后为触发器动作
+	}
+	if start < 0 {
+		panic("当前输入sql无法解析 " + x.originSql)
+	}
+	body.Text = strings.TrimSuffix(strings.TrimSpace(sql[start:]), consts.Semicolon)
+	if firstKeyword(body.Text) != consts.EXECUTE {
+		body.parseStatements()
+	}
+	if body.as {
+		start = regexp.MustCompile(`(?is)\sas\s$`).FindStringIndex(sql[:start])[0]
+	}
+	x.Body, x.tempSql = body, sql[:start]
+	return x
+}
+
+// 提取触发器头
+func (x *CreateTrigger) parseHeader() *CreateTrigger {
+	words := utils.SplitFieldsExcludeInBracket(compact(x.tempSql))
+	var events []string
+	for i := 1; i < len(words); i++ { // 跳过create
+		switch word := strings.ToLower(words[i]); {
+		case word == "or" && x.Name == consts.Empty:
+			x.OrReplace = true
+			i++
+		case strings.HasPrefix(word, "definer"):
+			x.Definer = x.restore(words[i])
+		case word == consts.TRIGGER && i+1 < len(words):
+			x.Name = words[i+1]
+			i++
+		case word == consts.BEFORE || word == consts.AFTER || word == "for" && x.ForEach == consts.Empty && i+1 < len(words) && !strings.EqualFold(words[i+1], consts.EACH):
+			x.Timing = word
+		case word == consts.INSTEAD:
+			x.Timing = "instead of"
+			i++
+		case word == consts.INSERT || word == consts.UPDATE || word == consts.DELETE || word == consts.TRUNCATE:
+			events = append(events, word)
+		case word == consts.OF && len(events) > 0: // update of a, b
+			var columns []string
+			for ; i+1 < len(words) && !strings.EqualFold(words[i+1], consts.ON) && !strings.EqualFold(words[i+1], "or"); i++ {
+				columns = append(columns, strings.TrimSuffix(words[i+1], consts.Comma))
+			}
+			events[len(events)-1] += " of " + strings.Join(columns, ", ")
+		case word == "or" || word == consts.Comma:
+		case word == consts.ON && i+1 < len(words):
+			x.Table, x.tableFirst = NewTable(words[i+1]), x.Timing == consts.Empty
+			i++
+		case word == "for" && i+2 < len(words) && strings.EqualFold(words[i+1], consts.EACH):
+			x.ForEach = strings.ToLower(words[i+2])
+			i += 2
+		case (word == "follows" || word == "precedes") && i+1 < len(words):
+			x.Order = word + consts.Blank + words[i+1]
+			i++
+		case word == consts.WHEN && i+1 < len(words):
+			x.When = x.restore(utils.TrimBrackets(words[i+1]))
+			i++
+		case word == consts.AS:
+		default:
+			if word = strings.Trim(word, consts.Comma); word == consts.INSERT || word == consts.UPDATE || word == consts.DELETE {
+				events = append(events, word) // sql server：after insert, update
+			} else if x.Table != nil {
+				var option = []string{words[i]}
+				for ; i+1 < len(words) && !isTriggerClause(words[i+1]); i++ {
+					option = append(option, words[i+1])
+				}
+				x.Options = append(x.Options, x.restore(strings.Join(option, consts.Blank)))
+			}
+		}
+	}
+	if x.Name == consts.Empty || x.Table == nil {
+		panic("当前输入sql无法解析 " + x.originSql)
+	}
+	x.Events = events
+	return x
+}
+
+// 是否触发器子句关键字
+func isTriggerClause(word string) bool {
+	switch strings.ToLower(word) {
+	case "for", consts.WHEN, consts.AS, consts.BEFORE, consts.AFTER, consts.INSTEAD, "referencing", "follows", "precedes", "from", consts.NOT, "deferrable", "initially":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,79 @@
+package beautify
+
+import (
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+)
+
+// ParseTruncateSQL 解析清空表SQL
+func ParseTruncateSQL(sql string, indent ...int) *Truncate {
+	// sql初始化
+	var parser = &Truncate{
+		Base: NewBase(sql, indent...),
+	}
+
+	// sql解析
+	parser.parsePrepare() // 解析准备
+	parser.parseTables()  // 解析清空表
+	parser.parseFinish()  // 解析完成
+
+	return parser
+}
+
+type Truncate struct {
+	Base
+	Only   bool     // 是否only（postgresql不作用于子表）
+	Tables []*Table // 清空表
+	Option string   // 清空选项（restart identity、cascade等）
+}
+
+// Beautify SQL美化输出
+func (x *Truncate) Beautify() string {
+	var sql = strings.Builder{}
+	sql.WriteString(consts.TRUNCATE)
+	sql.WriteString(consts.Blank)
+	sql.WriteString(consts.TABLE)
+	if x.Only {
+		sql.WriteString(" only")
+	}
+	for i, table := range x.Tables {
+		if i > 0 {
+			sql.WriteString(consts.Comma)
+		}
+		sql.WriteString(consts.Blank)
+		sql.WriteString(table.beautify())
+	}
+	if x.Option != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(x.Option)
+	}
+	return x.finish(sql.String())
+}
+
+// 提取清空表
+func (x *Truncate) parseTables() *Truncate {
+	sql := strings.TrimSuffix(strings.TrimSpace(x.tempSql), consts.Semicolon)
+	words := strings.Fields(sql)[1:] // 跳过truncate
+	words = skipWord(words, consts.TABLE)
+	if len(words) > 0 && strings.EqualFold(words[0], "only") {
+		x.Only, words = true, words[1:]
+	}
+	var names []string
+	for i, word := range words {
+		switch strings.ToLower(word) {
+		case "restart", "continue", consts.CASCADE, consts.RESTRICT:
+			x.Option = strings.ToLower(strings.Join(words[i:], consts.Blank))
+		}
+		if x.Option != consts.Empty {
+			break
+		}
+		names = append(names, word)
+	}
+	for _, name := range strings.Split(strings.Join(names, consts.Blank), consts.Comma) {
+		if name = strings.TrimSpace(name); name != consts.Empty {
+			x.Tables = append(x.Tables, NewTable(name))
+		}
+	}
+	return x
+}
@@ -0,0 +1,189 @@
+package beautify
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+	"github.com/go-xuan/sqlx/utils"
+)
+
+var (
+	withHintPattern = regexp.MustCompile(`(?i)\s+with\s*\(`)                                             // sql server表提示：with (nolock)
+	topPattern      = regexp.MustCompile(`(?i)^top\s*(\([^()]*\)|\d+)(\s+percent)?(\s+with\s+ties)?\s+`) // top (10) percent with ties
+	applyPattern    = regexp.MustCompile(`(?i)(^|\s)(cross|outer)\s+apply\s`)                            // cross apply、outer apply
+	outputPattern   = regexp.MustCompile(`(?i)\soutput\s`)                                               // output inserted.*
+	offsetPattern   = regexp.MustCompile(`(?i)\soffset\s+(\S+\s+rows?)(\s+fetch\s+(.+))?$`)              // offset 10 rows fetch next 10 rows only
+	fetchPattern    = regexp.MustCompile(`(?i)\sfetch\s+((first|next)\s.+)$`)                            // fetch first 10 rows only
+	fetchKeywords   = map[string]bool{"first": true, "next": true, "row": true, "rows": true, "only": true, "with": true, "ties": true, "percent": true}
+)
+
+// Top sql server限数：top (10) percent with ties
+type Top struct {
+	Count    string // 数量，包含括号时保留括号（(10)、(@n)）
+	Percent  bool   // 是否按百分比
+	WithTies bool   // 是否包含并列行
+}
+
+func (t *Top) beautify() string {
+	var sql = strings.Builder{}
+	sql.WriteString("top ")
+	sql.WriteString(t.Count)
+	if t.Percent {
+		sql.WriteString(" percent")
+	}
+	if t.WithTies {
+		sql.WriteString(" with ties")
+	}
+	return sql.String()
+}
+
+// 提取top，返回去除top之后的字段sql
+func (x *Select) parseTop(sql string) string {
+	if match := topPattern.FindStringSubmatch(sql); match != nil {
+		x.Top = &Top{
+			Count:    strings.Join(strings.Fields(match[1]), consts.Blank),
+			Percent:  match[2] != consts.Empty,
+			WithTies: match[3] != consts.Empty,
+		}
+		return sql[len(match[0]):]
+	}
+	return sql
+}
+
+// 提取offset/fetch（offset 10 rows fetch next 10 rows only），仅处理当前查询层级，offset需要位于order by之后以免与同名字段混淆
+func (x *Select) parseOffsetFetch() *Select {
+	if !x.supports(OffsetFetchClause) {
+		return x
+	}
+	sql := x.tempSql
+	var start = utils.IndexOfString(sql, consts.RightBracket, -1) + 1
+	if match := offsetPattern.FindStringSubmatchIndex(sql[start:]); match != nil && utils.IndexExcludeBrackets(strings.ToLower(sql[:start+match[0]]), consts.ORDERBY, true) > 0 {
+		x.Offset = fetchWords(sql[start+match[2] : start+match[3]])
+		if match[6] >= 0 {
+			x.Fetch = fetchWords(sql[start+match[6] : start+match[7]])
+		}
+		sql = sql[:start+match[0]]
+	} else if match = fetchPattern.FindStringSubmatchIndex(sql[start:]); match != nil {
+		x.Fetch = fetchWords(sql[start+match[2] : start+match[3]])
+		sql = sql[:start+match[0]]
+	}
+	x.tempSql = sql
+	return x
+}
+
+// 规范offset/fetch中的关键字为小写
+func fetchWords(sql string) string {
+	words := strings.Fields(sql)
+	for i, word := range words {
+		if lower := strings.ToLower(word); fetchKeywords[lower] {
+			words[i] = lower
+		}
+	}
+	return strings.Join(words, consts.Blank)
+}
+
+func (x *Select) beautifyOffsetFetch() string {
+	sql := strings.Builder{}
+	if x.Offset != consts.Empty {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(x.align(consts.OFFSET))
+		sql.WriteString(consts.Blank)
+		sql.WriteString(x.Offset)
+	}
+	if x.Fetch != consts.Empty {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(x.align(consts.FETCH))
+		sql.WriteString(consts.Blank)
+		sql.WriteString(x.Fetch)
+	}
+	return sql.String()
+}
+
+// 提取sql server表提示（with (nolock)、with (index(ix_a), updlock)）并按紧邻的表名或别名归类，返回去除表提示之后的sql
+func extractWithHints(sql string, hints map[string][]string) string {
+	matches := withHintPattern.FindAllStringIndex(sql, -1)
+	for i := len(matches) - 1; i >= 0; i-- { // 倒序处理，避免截取之后下标失效
+		match := matches[i]
+		if prefix := sql[:match[0]]; strings.Count(prefix, consts.LeftBracket) != strings.Count(prefix, consts.RightBracket) {
+			continue
+		} else if words := strings.Fields(prefix); len(words) > 0 && strings.EqualFold(words[len(words)-1], "start") {
+			continue // oracle：start with (...)
+		}
+		if from, to := utils.BetweenOfString(sql[match[1]-1:], consts.LeftBracket, consts.RightBracket); from == 0 && to > 0 {
+			args := sql[match[1] : match[1]-1+to]
+			addTableHint(hints, sql[:match[0]], consts.WITH+consts.Blank+strings.ToLower(joinHintArgs(args)))
+			sql = sql[:match[0]] + sql[match[1]+to:]
+		}
+	}
+	return sql
+}
+
+// 标记cross apply、outer apply为关联，统一为"cross apply join"以便和join一并拆分，仅处理当前查询层级
+func markApplies(sql string) string {
+	var builder = strings.Builder{}
+	var offset int
+	for _, match := range applyPattern.FindAllStringSubmatchIndex(sql, -1) {
+		if prefix := sql[:match[0]]; strings.Count(prefix, consts.LeftBracket) != strings.Count(prefix, consts.RightBracket) {
+			continue
+		}
+		builder.WriteString(sql[offset:match[3]])
+		builder.WriteString(strings.ToLower(sql[match[4]:match[5]]))
+		builder.WriteString(" apply join ")
+		offset = match[1]
+	}
+	builder.WriteString(sql[offset:])
+	return builder.String()
+}
+
+// 是否apply关联（cross apply、outer apply），apply关联没有on条件且不输出join关键字
+func isApply(joinType string) bool {
+	return strings.HasSuffix(joinType, consts.APPLY)
+}
+
+// Output 输出子句：sql server output inserted.id into @t、oracle/postgresql returning id into :id
+type Output struct {
+	Keyword string   // 子句关键字（output、returning）
+	Fields  []string // 输出字段
+	Into    string   // 输出目标（表、表变量或绑定变量）
+}
+
+// 截取sql中的output子句，返回output之前的sql以及output子句
+func cutOutput(sql string) (string, *Output) {
+	for _, match := range outputPattern.FindAllStringIndex(sql, -1) {
+		if prefix := sql[:match[0]]; strings.Count(prefix, consts.LeftBracket) == strings.Count(prefix, consts.RightBracket) {
+			return prefix, NewOutput(consts.OUTPUT, sql[match[1]:])
+		}
+	}
+	return sql, nil
+}
+
+// NewOutput 解析输出子句（不包含子句关键字）
+func NewOutput(keyword, sql string) *Output {
+	var output = &Output{Keyword: keyword}
+	if index := utils.IndexExcludeBrackets(strings.ToLower(sql), consts.INTO, true); index > 0 {
+		output.Into = strings.TrimSpace(sql[index+4:])
+		sql = sql[:index]
+	}
+	list, last := utils.SplitExcludeInBracket(sql, consts.Comma)
+	for _, field := range append(list, last) {
+		if field = strings.TrimSpace(field); field != consts.Empty {
+			output.Fields = append(output.Fields, field)
+		}
+	}
+	return output
+}
+
+func (o *Output) beautify() string {
+	var sql = strings.Builder{}
+	sql.WriteString(o.Keyword)
+	sql.WriteString(consts.Blank)
+	sql.WriteString(strings.Join(o.Fields, ", "))
+	if o.Into != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.INTO)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(o.Into)
+	}
+	return sql.String()
+}
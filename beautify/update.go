@@ -9,25 +9,37 @@ import (
 
 // ParseUpdateSQL 解析更新SQL
 func ParseUpdateSQL(sql string, indent ...int) *Update {
+	return parseUpdate(sql, nil, indent...)
+}
+
+// 按方言解析更新SQL，未指定方言时为nil
+func parseUpdate(sql string, dialect Dialect, indent ...int) *Update {
 	// sql初始化
 	var parser = &Update{
 		Base: NewBase(sql, indent...),
 	}
+	parser.dialect = dialect
 	// sql解析
-	parser.parsePrepare() // 解析准备
-	parser.parseTable()   // 解析主表
-	parser.parseFields()  // 解析字段
-	parser.parseWhere()   // 解析where
-	parser.parseFinish()  // 解析完成
+	parser.parsePrepare()   // 解析准备
+	parser.parseReturning() // 解析returning
+	parser.parseOrderBy()   // 解析order by以及limit
+	parser.parseTable()     // 解析主表
+	parser.parseFields()    // 解析字段
+	parser.parseWhere()     // 解析where
+	parser.parseFinish()    // 解析完成
 
 	return parser
 }
 
 type Update struct {
 	Base
-	Table  *Table       // 更新表
-	Fields []*Field     // 更新字段
-	Where  []*Condition // 查询条件
+	Table     *Table       // 更新表
+	Fields    []*Field     // 更新字段
+	Where     []*Condition // 查询条件
+	OrderBy   []string     // 排序条件（mysql）
+	Limit     string       // 限数条件（mysql）
+	Output    *Output      // sql server：output inserted.*
+	Returning *Output      // oracle/postgresql：returning id into :id
 }
 
 func (x *Update) Beautify() string {
@@ -35,19 +47,20 @@ func (x *Update) Beautify() string {
 	sql.WriteString(x.beautifyUpdate())
 	sql.WriteString(x.beautifyFields())
 	sql.WriteString(x.beautifyCondition())
-	if replacer := x.replacer; replacer != nil {
-		return replacer.Replace(sql.String())
-	} else {
-		return sql.String()
+	sql.WriteString(x.beautifyOrderByLimit(x.OrderBy, x.Limit))
+	if x.Returning != nil {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(x.align(x.Returning.beautify()))
 	}
+	return x.finish(sql.String())
 }
 
 // 构建查询字段sql
 func (x *Update) beautifyUpdate() string {
 	var sql = strings.Builder{}
-	sql.WriteString(consts.UPDATE)
+	sql.WriteString(Align(x.format().Indent, consts.UPDATE))
 	sql.WriteString(consts.Blank)
-	sql.WriteString(x.Table.beautify())
+	sql.WriteString(x.Table.beautify(x.format().aliasAs(false)))
 	sql.WriteString(consts.NextLine)
 	return sql.String()
 }
@@ -57,7 +70,7 @@ func (x *Update) beautifyFields() string {
 	var sql = strings.Builder{}
 	var maxLen int
 	for _, field := range x.Fields {
-		l := len(field.Name)
+		l := utils.DisplayWidth(field.column())
 		if maxLen < l {
 			maxLen = l
 		}
@@ -73,14 +86,18 @@ func (x *Update) beautifyFields() string {
 				sql.WriteString(x.align())
 			}
 			sql.WriteString(consts.Blank)
-			sql.WriteString(field.Name)
-			sql.WriteString(strings.Repeat(consts.Blank, maxLen-len(field.Name)+1))
+			sql.WriteString(field.column())
+			sql.WriteString(strings.Repeat(consts.Blank, maxLen-utils.DisplayWidth(field.column())+1))
 			sql.WriteString(consts.EQ)
 			sql.WriteString(consts.Blank)
 			sql.WriteString(field.Value)
 			i++
 		}
 	}
+	if x.Output != nil {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(x.align(x.Output.beautify()))
+	}
 	return sql.String()
 }
 
@@ -90,18 +107,11 @@ func (x *Update) beautifyCondition() string {
 		sql.WriteString(consts.NextLine)
 		sql.WriteString(x.align(consts.WHERE))
 		sql.WriteString(consts.Blank)
-		for i, cond := range conditions {
+		for i, condition := range conditions {
 			if i > 0 {
 				sql.WriteString(consts.NextLine)
-				if cond.AndOr == consts.Empty {
-					sql.WriteString(x.align(consts.AND))
-					sql.WriteString(consts.Blank)
-				} else {
-					sql.WriteString(x.align(cond.AndOr))
-					sql.WriteString(consts.Blank)
-				}
 			}
-			sql.WriteString(cond.Value)
+			sql.WriteString(condition.beautify(x.indent))
 		}
 	}
 	return sql.String()
@@ -122,15 +132,16 @@ func (x *Update) parseTable() *Update {
 		x.tempSql = sql[index:]
 		sql = sql[:index]
 	}
+	var hints = make(map[string][]string)
+	sql = x.extractTableHints(sql, hints)
 	var name, alias string
-	if index := utils.IndexOfString(sql, consts.Blank, 1); index >= 0 {
+	if index := utils.IndexExcludeQuotes(sql, consts.Blank, 0); index >= 0 {
 		name = sql[:index]
 		alias = utils.ExtractAlias(sql[index+1:])
 	}
-	x.Table = &Table{
-		Name:  name,
-		Alias: alias,
-	}
+	x.Table = NewTable(name)
+	x.Table.Alias = alias
+	attachTableHints(x.Table, hints)
 	return x
 }
 
@@ -142,6 +153,9 @@ func (x *Update) parseFields() *Update {
 		x.tempSql = sql[index:]
 		sql = sql[:index]
 	}
+	if x.supports(OutputClause) {
+		sql, x.Output = cutOutput(sql)
+	}
 	// 截取where关键字前面的sql片段
 	if index := utils.IndexOfKeywordFirst(sql, consts.SET); index >= 0 {
 		sql = sql[index+4:]
@@ -157,17 +171,27 @@ func (x *Update) parseFields() *Update {
 			if utils.IndexOfString(name, consts.ReplacePrefix) >= 0 {
 				name = x.replacer.Replace(name)
 			}
-			fields = append(fields, &Field{Name: name, Value: value})
+			field := NewColumn(name)
+			if field.Value = value; NewExpression(value) != nil {
+				field.Value = NewExpression(value).beautify()
+			}
+			fields = append(fields, field)
 		}
 		x.Fields = fields
 	}
 	return x
 }
 
-// 提取查询条件
+// 提取order by以及limit
+func (x *Update) parseOrderBy() *Update {
+	x.tempSql, x.OrderBy, x.Limit = cutOrderByLimit(x.tempSql)
+	return x
+}
+
+// 提取查询条件，order by以及limit已提前截取，where之后均为条件
 func (x *Update) parseWhere() *Update {
-	if sql := x.tempSql; sql != "" {
-		x.Where, x.tempSql = ExtractWhere(sql)
+	if index := utils.IndexOfKeywordFirst(x.tempSql, consts.WHERE); index >= 0 {
+		x.Where, x.tempSql = newConditions(x.tempSql[index+5:], x.dialect, x.indent), consts.Empty
 	}
 	return x
 }
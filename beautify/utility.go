@@ -0,0 +1,587 @@
+package beautify
+
+import (
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+	"github.com/go-xuan/sqlx/utils"
+)
+
+// ParseExplainSQL 解析执行计划SQL，被解释的语句单独解析美化
+func ParseExplainSQL(sql string, indent ...int) *Explain {
+	return parseExplain(sql, nil, indent...)
+}
+
+// 按方言解析执行计划SQL，未指定方言时为nil
+func parseExplain(sql string, dialect Dialect, indent ...int) *Explain {
+	// sql初始化
+	var parser = &Explain{
+		Base: NewBase(sql, indent...),
+	}
+	parser.dialect = dialect
+
+	// sql解析
+	parser.parsePrepare()   // 解析准备
+	parser.parseOptions()   // 解析执行计划选项
+	parser.parseStatement() // 解析被解释的语句
+	parser.parseFinish()    // 解析完成
+
+	return parser
+}
+
+type Explain struct {
+	Base
+	Analyze   bool     // 是否analyze
+	Options   []string // 执行计划选项（verbose、format=json、(analyze, buffers)、query plan）
+	Statement IParser  // 被解释的语句
+}
+
+// Beautify SQL美化输出
+func (x *Explain) Beautify() string {
+	var sql = strings.Builder{}
+	sql.WriteString(consts.EXPLAIN)
+	if x.Analyze {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.ANALYZE)
+	}
+	for _, option := range x.Options {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(option)
+	}
+	sql.WriteString(consts.NextLine)
+	sql.WriteString(x.Statement.Beautify())
+	return x.finish(sql.String())
+}
+
+// 提取执行计划选项
+func (x *Explain) parseOptions() *Explain {
+	sql := strings.TrimSuffix(strings.TrimSpace(x.tempSql), consts.Semicolon)
+	words := utils.SplitFieldsExcludeInBracket(sql)[1:] // 跳过explain
+	var i int
+	for ; i < len(words); i++ {
+		word := strings.ToLower(words[i])
+		if isExplainable(word) {
+			break
+		} else if word == consts.ANALYZE || word == "analyse" {
+			x.Analyze = true
+		} else {
+			x.Options = append(x.Options, word)
+		}
+	}
+	if i == len(words) {
+		panic("当前输入sql无法解析 " + x.originSql)
+	}
+	x.tempSql = strings.Join(words[i:], consts.Blank)
+	return x
+}
+
+// 提取被解释的语句
+func (x *Explain) parseStatement() *Explain {
+	x.Statement = Parse(x.tempSql, WithDialect(x.dialect))
+	return x
+}
+
+// 是否可被explain解释的语句开头关键字，执行计划选项到此为止
+func isExplainable(word string) bool {
+	switch word {
+	case consts.WITH, consts.SELECT, consts.INSERT, consts.UPDATE, consts.DELETE, consts.MERGE, consts.CREATE:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseSetSQL 解析设置变量SQL
+func ParseSetSQL(sql string, indent ...int) *Set {
+	// sql初始化
+	var parser = &Set{
+		Base: NewBase(sql, indent...),
+	}
+
+	// sql解析
+	parser.parsePrepare() // 解析准备
+	parser.parseScope()   // 解析作用域
+	parser.parseItems()   // 解析变量赋值
+	parser.parseFinish()  // 解析完成
+
+	return parser
+}
+
+type Set struct {
+	Base
+	Scope string   // 作用域（session、local、global）
+	Items []*Field // 变量赋值，Name为变量名，Value为变量值，无赋值符号时（set names utf8）Value为空
+}
+
+// Beautify SQL美化输出
+func (x *Set) Beautify() string {
+	var sql = strings.Builder{}
+	sql.WriteString(consts.SET)
+	sql.WriteString(consts.Blank)
+	if x.Scope != consts.Empty {
+		sql.WriteString(x.Scope)
+		sql.WriteString(consts.Blank)
+	}
+	for i, item := range x.Items {
+		if i > 0 {
+			sql.WriteString(consts.Comma)
+			sql.WriteString(consts.NextLine)
+			sql.WriteString(Align(4))
+		}
+		sql.WriteString(item.Name)
+		if item.Value != consts.Empty {
+			sql.WriteString(consts.Blank)
+			sql.WriteString(consts.EQ)
+			sql.WriteString(consts.Blank)
+			sql.WriteString(item.Value)
+		}
+	}
+	return x.finish(sql.String())
+}
+
+// 提取作用域
+func (x *Set) parseScope() *Set {
+	sql := strings.TrimSuffix(strings.TrimSpace(x.tempSql), consts.Semicolon)
+	words := strings.Fields(sql)[1:] // 跳过set
+	if len(words) > 1 {
+		switch scope := strings.ToLower(words[0]); scope {
+		case "session", "local", "global", "persist":
+			x.Scope, words = scope, words[1:]
+		}
+	}
+	if len(words) == 0 {
+		panic("当前输入sql无法解析 " + x.originSql)
+	}
+	x.tempSql = strings.Join(words, consts.Blank)
+	return x
+}
+
+// 提取变量赋值（set a = 1, b = 2、set search_path to a, b）
+func (x *Set) parseItems() *Set {
+	list, last := utils.SplitExcludeInBracket(x.tempSql, consts.Comma)
+	for _, item := range append(list, last) {
+		item = strings.TrimSpace(item)
+		name, value := utils.CutString(item, consts.EQ)
+		if value == consts.Empty {
+			if words := strings.SplitN(item, consts.Blank, 3); len(words) == 3 && strings.EqualFold(words[1], consts.TO) {
+				name, value = words[0], words[2]
+			}
+		} else if strings.HasSuffix(name, ":") { // mysql：set @a := 1
+			name = strings.TrimSuffix(name, ":")
+		}
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		// 无赋值的片段为上一个变量的多值（set search_path = a, b）
+		if l := len(x.Items); l > 0 && value == consts.Empty && x.Items[l-1].Value != consts.Empty {
+			x.Items[l-1].Value += consts.Comma + consts.Blank + name
+		} else if value == consts.Empty { // set names utf8、set transaction isolation level read committed
+			x.Items = append(x.Items, &Field{Name: strings.ToLower(name)})
+		} else {
+			x.Items = append(x.Items, &Field{Name: name, Value: value})
+		}
+	}
+	return x
+}
+
+// ParseDeclareSQL 解析变量声明SQL（sql server：declare @a int = 1, @b varchar(10)）
+func ParseDeclareSQL(sql string, indent ...int) *Declare {
+	// sql初始化
+	var parser = &Declare{
+		Base: NewBase(sql, indent...),
+	}
+
+	// sql解析
+	parser.parsePrepare()   // 解析准备
+	parser.parseVariables() // 解析变量
+	parser.parseFinish()    // 解析完成
+
+	return parser
+}
+
+type Declare struct {
+	Base
+	Variables []*Field // 声明变量，Name为变量名，Type为变量类型，Value为初始值
+}
+
+// Beautify SQL美化输出
+func (x *Declare) Beautify() string {
+	var sql = strings.Builder{}
+	sql.WriteString(consts.DECLARE)
+	sql.WriteString(consts.Blank)
+	for i, variable := range x.Variables {
+		if i > 0 {
+			sql.WriteString(consts.Comma)
+			sql.WriteString(consts.NextLine)
+			sql.WriteString(Align(8))
+		}
+		sql.WriteString(variable.Name)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(variable.Type)
+		if variable.Value != consts.Empty {
+			sql.WriteString(consts.Blank)
+			sql.WriteString(consts.EQ)
+			sql.WriteString(consts.Blank)
+			sql.WriteString(variable.Value)
+		}
+	}
+	return x.finish(sql.String())
+}
+
+// 提取声明变量（declare @a as int = 1、declare @t table (id int)）
+func (x *Declare) parseVariables() *Declare {
+	sql := strings.TrimSuffix(strings.TrimSpace(x.tempSql), consts.Semicolon)
+	if len(sql) <= 8 {
+		panic("当前输入sql无法解析 " + x.originSql)
+	}
+	list, last := utils.SplitExcludeInBracket(sql[8:], consts.Comma)
+	for _, item := range append(list, last) {
+		name, typ := utils.CutString(strings.TrimSpace(item), consts.Blank)
+		if typ = strings.TrimSpace(typ); strings.HasPrefix(strings.ToLower(typ), consts.AS+consts.Blank) {
+			typ = strings.TrimSpace(typ[3:])
+		}
+		if name == consts.Empty || typ == consts.Empty {
+			panic("当前输入sql无法解析 " + x.originSql)
+		}
+		var variable = &Field{Name: name}
+		if index := utils.IndexExcludeBrackets(typ, consts.EQ, false); index > 0 {
+			variable.Value = strings.TrimSpace(typ[index+1:])
+			typ = typ[:index]
+		}
+		if variable.Type = castType(typ); strings.HasPrefix(variable.Type, consts.TABLE+consts.LeftBracket) { // 表变量保留表名与字段定义之间的空格
+			variable.Type = consts.TABLE + consts.Blank + variable.Type[5:]
+		}
+		x.Variables = append(x.Variables, variable)
+	}
+	return x
+}
+
+// ParseUseSQL 解析切换数据库SQL
+func ParseUseSQL(sql string, indent ...int) *Use {
+	// sql初始化
+	var parser = &Use{
+		Base: NewBase(sql, indent...),
+	}
+
+	// sql解析
+	parser.parsePrepare()  // 解析准备
+	parser.parseDatabase() // 解析数据库
+	parser.parseFinish()   // 解析完成
+
+	return parser
+}
+
+type Use struct {
+	Base
+	Database string // 数据库
+}
+
+// Beautify SQL美化输出
+func (x *Use) Beautify() string {
+	return x.finish(consts.USE + consts.Blank + x.Database)
+}
+
+// 提取数据库
+func (x *Use) parseDatabase() *Use {
+	sql := strings.TrimSuffix(strings.TrimSpace(x.tempSql), consts.Semicolon)
+	if words := strings.Fields(sql); len(words) == 2 {
+		x.Database = words[1]
+	} else {
+		panic("当前输入sql无法解析 " + x.originSql)
+	}
+	return x
+}
+
+// ParseShowSQL 解析show语句SQL
+func ParseShowSQL(sql string, indent ...int) *Show {
+	return parseShow(sql, nil, indent...)
+}
+
+// 按方言解析show语句SQL，未指定方言时为nil
+func parseShow(sql string, dialect Dialect, indent ...int) *Show {
+	// sql初始化
+	var parser = &Show{
+		Base: NewBase(sql, indent...),
+	}
+	parser.dialect = dialect
+
+	// sql解析
+	parser.parsePrepare() // 解析准备
+	parser.parseWhere()   // 解析过滤条件
+	parser.parseObject()  // 解析展示对象
+	parser.parseFinish()  // 解析完成
+
+	return parser
+}
+
+type Show struct {
+	Base
+	Object string       // 展示对象（tables、create table t、columns from t）
+	Like   string       // like过滤
+	Where  []*Condition // where过滤
+}
+
+// Beautify SQL美化输出
+func (x *Show) Beautify() string {
+	var sql = strings.Builder{}
+	sql.WriteString(consts.SHOW)
+	sql.WriteString(consts.Blank)
+	sql.WriteString(x.Object)
+	if x.Like != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.LIKE)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(x.Like)
+	}
+	for i, condition := range x.Where {
+		sql.WriteString(consts.Blank)
+		if i == 0 {
+			sql.WriteString(consts.WHERE)
+			sql.WriteString(consts.Blank)
+		}
+		sql.WriteString(condition.beautify(0))
+	}
+	return x.finish(sql.String())
+}
+
+// 提取过滤条件
+func (x *Show) parseWhere() *Show {
+	sql := strings.TrimSuffix(strings.TrimSpace(x.tempSql), consts.Semicolon)
+	lower := strings.ToLower(sql)
+	if index := utils.IndexExcludeBrackets(lower, consts.WHERE, true); index >= 0 {
+		x.Where, sql = newConditions(sql[index+6:], x.dialect, x.indent), strings.TrimSpace(sql[:index])
+	} else if index = utils.IndexExcludeBrackets(lower, consts.LIKE, true); index >= 0 {
+		x.Like, sql = strings.TrimSpace(sql[index+5:]), strings.TrimSpace(sql[:index])
+	}
+	x.tempSql = sql
+	return x
+}
+
+// 提取展示对象
+func (x *Show) parseObject() *Show {
+	words := strings.Fields(x.tempSql)
+	if len(words) < 2 {
+		panic("当前输入sql无法解析 " + x.originSql)
+	}
+	for i, word := range words[1:] {
+		switch lower := strings.ToLower(word); lower {
+		case consts.FROM, consts.IN, consts.TABLE, consts.INDEX, consts.VIEW, consts.CREATE, consts.COLUMN, "columns", "tables",
+			"databases", "schemas", "full", "status", "variables", "processlist", "grants", "for", "indexes", "keys":
+			words[i+1] = lower
+		}
+	}
+	x.Object = strings.Join(words[1:], consts.Blank)
+	return x
+}
+
+// ParseGrantSQL 解析授权、撤销授权SQL
+func ParseGrantSQL(sql string, indent ...int) *Grant {
+	// sql初始化
+	var parser = &Grant{
+		Base: NewBase(sql, indent...),
+	}
+
+	// sql解析
+	parser.parsePrepare()    // 解析准备
+	parser.parseOption()     // 解析授权选项
+	parser.parseGrantees()   // 解析被授权对象
+	parser.parsePrivileges() // 解析权限和授权对象
+	parser.parseFinish()     // 解析完成
+
+	return parser
+}
+
+type Grant struct {
+	Base
+	Revoke     bool     // 是否撤销授权
+	Privileges []string // 权限（select、insert、all privileges）或者角色
+	Object     string   // 授权对象（t、table t、all tables in schema s），角色授权时为空
+	Grantees   []string // 被授权用户或角色
+	Option     string   // 授权选项（grant：with grant option；revoke：grant option for）
+	Cascade    string   // 撤销选项（cascade、restrict）
+}
+
+// Beautify SQL美化输出
+func (x *Grant) Beautify() string {
+	var sql = strings.Builder{}
+	if x.Revoke {
+		sql.WriteString(consts.REVOKE)
+		if x.Option != consts.Empty {
+			sql.WriteString(consts.Blank)
+			sql.WriteString(x.Option)
+		}
+	} else {
+		sql.WriteString(consts.GRANT)
+	}
+	sql.WriteString(consts.Blank)
+	sql.WriteString(strings.Join(x.Privileges, ", "))
+	if x.Object != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.ON)
+		sql.WriteString(consts.Blank)
+		sql.WriteString(x.Object)
+	}
+	sql.WriteString(consts.Blank)
+	if x.Revoke {
+		sql.WriteString(consts.FROM)
+	} else {
+		sql.WriteString(consts.TO)
+	}
+	sql.WriteString(consts.Blank)
+	sql.WriteString(strings.Join(x.Grantees, ", "))
+	if !x.Revoke && x.Option != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(x.Option)
+	}
+	if x.Cascade != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(x.Cascade)
+	}
+	return x.finish(sql.String())
+}
+
+// 提取授权选项
+func (x *Grant) parseOption() *Grant {
+	sql := strings.TrimSuffix(strings.TrimSpace(x.tempSql), consts.Semicolon)
+	words := strings.Fields(sql)
+	x.Revoke = strings.EqualFold(words[0], consts.REVOKE)
+	words = words[1:]
+	if l := len(words); l > 0 && (strings.EqualFold(words[l-1], consts.CASCADE) || strings.EqualFold(words[l-1], consts.RESTRICT)) {
+		x.Cascade, words = strings.ToLower(words[l-1]), words[:l-1]
+	}
+	if x.Revoke { // revoke grant option for ...
+		if len(words) > 3 && strings.EqualFold(words[2], consts.FOR) && strings.EqualFold(words[1], "option") {
+			x.Option, words = strings.ToLower(strings.Join(words[:3], consts.Blank)), words[3:]
+		}
+	} else if l := len(words); l > 3 && strings.EqualFold(words[l-3], consts.WITH) && strings.EqualFold(words[l-1], "option") {
+		x.Option, words = strings.ToLower(strings.Join(words[l-3:], consts.Blank)), words[:l-3]
+	}
+	x.tempSql = strings.Join(words, consts.Blank)
+	return x
+}
+
+// 提取被授权对象
+func (x *Grant) parseGrantees() *Grant {
+	sql := x.tempSql
+	key := consts.TO
+	if x.Revoke {
+		key = consts.FROM
+	}
+	index := utils.IndexOfKeywordLast(strings.ToLower(sql), key)
+	if index < 0 {
+		panic("当前输入sql无法解析 " + x.originSql)
+	}
+	list, last := utils.SplitExcludeInBracket(sql[index+len(key)+1:], consts.Comma)
+	for _, grantee := range append(list, last) {
+		x.Grantees = append(x.Grantees, strings.TrimSpace(grantee))
+	}
+	x.tempSql = strings.TrimSpace(sql[:index])
+	return x
+}
+
+// 提取权限和授权对象
+func (x *Grant) parsePrivileges() *Grant {
+	sql := x.tempSql
+	if index := utils.IndexExcludeBrackets(strings.ToLower(sql), consts.ON, true); index >= 0 {
+		x.Object, sql = strings.TrimSpace(sql[index+3:]), strings.TrimSpace(sql[:index])
+		words := strings.Fields(x.Object)
+		for i := 0; i < len(words)-1; i++ { // 对象类型关键字小写（table t、all tables in schema s）
+			words[i] = strings.ToLower(words[i])
+		}
+		x.Object = strings.Join(words, consts.Blank)
+	}
+	list, last := utils.SplitExcludeInBracket(sql, consts.Comma)
+	for _, privilege := range append(list, last) {
+		privilege = strings.TrimSpace(privilege)
+		if x.Object != consts.Empty {
+			privilege = strings.ToLower(privilege)
+		}
+		x.Privileges = append(x.Privileges, privilege)
+	}
+	return x
+}
+
+// ParseTransactionSQL 解析事务控制SQL
+func ParseTransactionSQL(sql string, indent ...int) *Transaction {
+	// sql初始化
+	var parser = &Transaction{
+		Base: NewBase(sql, indent...),
+	}
+
+	// sql解析
+	parser.parsePrepare() // 解析准备
+	parser.parseAction()  // 解析事务动作
+	parser.parseFinish()  // 解析完成
+
+	return parser
+}
+
+type Transaction struct {
+	Base
+	Action    string // 事务动作（begin、start transaction、commit、rollback、savepoint、release）
+	Savepoint string // 保存点名称
+	Options   string // 事务选项（isolation level read committed、read only、work）
+}
+
+// Beautify SQL美化输出
+func (x *Transaction) Beautify() string {
+	var sql = strings.Builder{}
+	sql.WriteString(x.Action)
+	if x.Options != consts.Empty {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(x.Options)
+	}
+	if x.Savepoint != consts.Empty {
+		sql.WriteString(consts.Blank)
+		if x.Action != consts.SAVEPOINT {
+			sql.WriteString(x.toSavepoint())
+		}
+		sql.WriteString(x.Savepoint)
+	}
+	return x.finish(sql.String())
+}
+
+func (x *Transaction) toSavepoint() string {
+	if x.Action == consts.ROLLBACK {
+		return consts.TO + consts.Blank + consts.SAVEPOINT + consts.Blank
+	}
+	return consts.SAVEPOINT + consts.Blank
+}
+
+// 提取事务动作
+func (x *Transaction) parseAction() *Transaction {
+	sql := strings.TrimSuffix(strings.TrimSpace(x.tempSql), consts.Semicolon)
+	words := strings.Fields(strings.ToLower(sql))
+	x.Action, words = words[0], words[1:]
+	switch x.Action {
+	case consts.START:
+		if len(words) == 0 || words[0] != consts.TRANSACTION {
+			panic("当前输入sql无法解析 " + x.originSql)
+		}
+		x.Action, words = consts.START+consts.Blank+consts.TRANSACTION, words[1:]
+	case consts.SAVEPOINT:
+		if len(words) != 1 {
+			panic("当前输入sql无法解析 " + x.originSql)
+		}
+		x.Savepoint, words = strings.Fields(sql)[1], nil
+	case consts.ROLLBACK, consts.RELEASE:
+		// rollback [work] to [savepoint] s、release [savepoint] s
+		if x.Action == consts.ROLLBACK {
+			if len(words) > 0 && (words[0] == "work" || words[0] == consts.TRANSACTION) {
+				x.Options, words = words[0], words[1:]
+			}
+			if len(words) == 0 || words[0] != consts.TO {
+				break
+			}
+			words = words[1:]
+		}
+		words = skipWord(words, consts.SAVEPOINT)
+		if len(words) != 1 {
+			panic("当前输入sql无法解析 " + x.originSql)
+		}
+		fields := strings.Fields(sql)
+		x.Savepoint, words = fields[len(fields)-1], nil
+	}
+	if len(words) > 0 {
+		x.Options = strings.Join(words, consts.Blank)
+	}
+	return x
+}
@@ -0,0 +1,170 @@
+package beautify
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-xuan/sqlx/consts"
+	"github.com/go-xuan/sqlx/utils"
+)
+
+// ParseCreateViewSQL 解析创建视图SQL
+func ParseCreateViewSQL(sql string, indent ...int) *CreateView {
+	return parseCreateView(sql, nil, indent...)
+}
+
+// 按方言解析创建视图SQL，未指定方言时为nil
+func parseCreateView(sql string, dialect Dialect, indent ...int) *CreateView {
+	// sql初始化
+	var parser = &CreateView{
+		Base: NewBase(sql, indent...),
+	}
+	parser.dialect = dialect
+
+	// sql解析
+	parser.parsePrepare() // 解析准备
+	parser.parseOption()  // 解析视图尾部选项
+	parser.parseView()    // 解析视图名
+	parser.parseQuery()   // 解析视图查询
+	parser.parseFinish()  // 解析完成
+
+	return parser
+}
+
+type CreateView struct {
+	Base
+	OrReplace    bool     // 是否or replace
+	Materialized bool     // 是否物化视图
+	IfNotExists  bool     // 是否if not exists
+	Table        *Table   // 视图
+	Columns      []string // 视图字段
+	Options      []string // 视图头部选项原文（algorithm=merge、definer=x、sql security definer、with (...)）
+	Query        *Select  // 视图查询
+	CheckOption  string   // 视图尾部选项（with check option、with no data）
+}
+
+// Beautify SQL美化输出
+func (x *CreateView) Beautify() string {
+	var sql = strings.Builder{}
+	sql.WriteString(consts.CREATE)
+	if x.OrReplace {
+		sql.WriteString(" or replace")
+	}
+	for _, option := range x.Options {
+		if !strings.HasPrefix(option, consts.WITH) {
+			sql.WriteString(consts.Blank)
+			sql.WriteString(option)
+		}
+	}
+	if x.Materialized {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.MATERIALIZED)
+	}
+	sql.WriteString(consts.Blank)
+	sql.WriteString(consts.VIEW)
+	if x.IfNotExists {
+		sql.WriteString(consts.Blank)
+		sql.WriteString(consts.IFNOTEXISTS)
+	}
+	sql.WriteString(consts.Blank)
+	sql.WriteString(x.Table.beautify())
+	if len(x.Columns) > 0 {
+		sql.WriteString(" (")
+		sql.WriteString(strings.Join(x.Columns, ", "))
+		sql.WriteString(consts.RightBracket)
+	}
+	for _, option := range x.Options {
+		if strings.HasPrefix(option, consts.WITH) {
+			sql.WriteString(consts.Blank)
+			sql.WriteString(option)
+		}
+	}
+	sql.WriteString(consts.Blank)
+	sql.WriteString(consts.AS)
+	sql.WriteString(consts.NextLine)
+	sql.WriteString(Align(viewIndent))
+	sql.WriteString(x.Query.Beautify())
+	if x.CheckOption != consts.Empty {
+		sql.WriteString(consts.NextLine)
+		sql.WriteString(x.CheckOption)
+	}
+	return x.finish(sql.String())
+}
+
+// 视图查询相对视图头部的缩进量
+const viewIndent = 4
+
+// 提取视图尾部选项
+func (x *CreateView) parseOption() *CreateView {
+	sql := strings.TrimSuffix(strings.TrimSpace(x.tempSql), consts.Semicolon)
+	if match := regexp.MustCompile(`(?i)\swith\s+((cascaded|local)\s+)?check\s+option$|\swith\s+(no\s+)?data$`).FindStringIndex(sql); match != nil {
+		x.CheckOption = strings.ToLower(strings.Join(strings.Fields(sql[match[0]:]), consts.Blank))
+		sql = sql[:match[0]]
+	}
+	x.tempSql = strings.TrimSpace(sql)
+	return x
+}
+
+// 提取视图名、字段和头部选项
+func (x *CreateView) parseView() *CreateView {
+	sql := x.tempSql
+	index := utils.IndexExcludeBrackets(strings.ToLower(sql), consts.AS, true)
+	if index < 0 {
+		panic("当前输入sql无法解析 " + x.originSql)
+	}
+	var header = sql[:index]
+	x.tempSql = strings.TrimSpace(sql[index+3:])
+	words := utils.SplitFieldsExcludeInBracket(header)
+	for i := 1; i < len(words); i++ { // 跳过create
+		switch word := strings.ToLower(words[i]); word {
+		case "or", "replace":
+			x.OrReplace = true
+		case consts.MATERIALIZED:
+			x.Materialized = true
+		case consts.VIEW:
+			if i+3 < len(words) && strings.EqualFold(strings.Join(words[i+1:i+4], consts.Blank), consts.IFNOTEXISTS) {
+				x.IfNotExists = true
+				i += 3
+			}
+			if i+1 < len(words) {
+				name, columns := splitNameColumns(words[i+1])
+				x.Table = NewTable(name)
+				if columns != consts.Empty {
+					x.Columns = splitColumns(columns)
+				}
+				i++
+			}
+		case consts.WITH:
+			if i+1 < len(words) {
+				x.Options = append(x.Options, consts.WITH+consts.Blank+x.restore(words[i+1]))
+				i++
+			}
+		case "sql":
+			if i+2 < len(words) { // sql security definer
+				x.Options = append(x.Options, strings.ToLower(strings.Join(words[i:i+3], consts.Blank)))
+				i += 2
+			}
+		default:
+			if x.Table != nil && strings.HasPrefix(word, consts.LeftBracket) {
+				x.Columns = splitColumns(words[i])
+			} else {
+				x.Options = append(x.Options, x.restore(words[i]))
+			}
+		}
+	}
+	if x.Table == nil {
+		panic("当前输入sql无法解析 " + x.originSql)
+	}
+	return x
+}
+
+// 提取视图查询
+func (x *CreateView) parseQuery() *CreateView {
+	sql := utils.TrimBrackets(x.tempSql)
+	if firstKeyword(sql) != consts.SELECT { // 暂不支持公共表表达式（with c as (...) select），避免丢失查询内容
+		panic("当前输入sql无法解析 " + x.originSql)
+	}
+	x.Query = parseSelect(sql, x.dialect, viewIndent)
+	x.tempSql = consts.Empty
+	return x
+}
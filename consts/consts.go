@@ -1,8 +1,10 @@
 package consts
 
 const (
-	ReplacePrefix = "value@"
-	ReplaceSuffix = "@"
+	ReplacePrefix   = "value@"
+	ReplaceSuffix   = "@"
+	ParamPrefix     = "param@" // 绑定占位符替换前缀
+	BodyPlaceholder = "body@"  // 函数体占位符
 )
 
 // symbol
@@ -12,6 +14,7 @@ const (
 	LeftBracket  = "("
 	RightBracket = ")"
 	Comma        = ","
+	Dot          = "."
 	Semicolon    = ";"
 	NextLine     = "\n"
 	EQ           = "="
@@ -24,46 +27,139 @@ const (
 
 // keyword
 const (
-	SELECT    = "select"
-	UPDATE    = "update"
-	DELETE    = "delete"
-	INSERT    = "insert"
-	INTO      = "into"
-	VALUE     = "value"
-	VALUES    = "values"
-	FROM      = "from"
-	WHERE     = "where"
-	SET       = "set"
-	LEFT      = "left"
-	RIGHT     = "right"
-	INNER     = "inner"
-	OUTER     = "outer"
-	JOIN      = "join"
-	GROUP     = "group"
-	GROUPBY   = "group by"
-	ORDER     = "order"
-	ORDERBY   = "order by"
-	HAVING    = "having"
-	LIMIT     = "limit"
-	OFFSET    = "offset"
-	AS        = "as"
-	AND       = "and"
-	ON        = "on"
-	OR        = "or"
-	IN        = "in"
-	NOTIN     = "not in"
-	IS        = "is"
-	ISNOT     = "is not"
-	NOT       = "not"
-	LIKE      = "like"
-	BY        = "by"
-	DISTINCT  = "distinct"
-	OVER      = "over"
-	PARTITION = "partition"
-	CASE      = "case"
-	WHEN      = "when"
-	THEN      = "then"
-	END       = "end"
-	ASC       = "asc"
-	DESC      = "desc"
+	SELECT       = "select"
+	UPDATE       = "update"
+	DELETE       = "delete"
+	INSERT       = "insert"
+	INTO         = "into"
+	VALUE        = "value"
+	VALUES       = "values"
+	FROM         = "from"
+	WHERE        = "where"
+	SET          = "set"
+	LEFT         = "left"
+	RIGHT        = "right"
+	INNER        = "inner"
+	OUTER        = "outer"
+	JOIN         = "join"
+	STRAIGHTJOIN = "straight_join"
+	CROSS        = "cross"
+	APPLY        = "apply"
+	GLOBAL       = "global"
+	ANY          = "any"
+	ALL          = "all"
+	GROUP        = "group"
+	GROUPBY      = "group by"
+	ORDER        = "order"
+	ORDERBY      = "order by"
+	HAVING       = "having"
+	LIMIT        = "limit"
+	OFFSET       = "offset"
+	FETCH        = "fetch"
+	AS           = "as"
+	AND          = "and"
+	ON           = "on"
+	OR           = "or"
+	IN           = "in"
+	NOTIN        = "not in"
+	IS           = "is"
+	ISNOT        = "is not"
+	NOT          = "not"
+	LIKE         = "like"
+	BY           = "by"
+	DISTINCT     = "distinct"
+	OVER         = "over"
+	PARTITION    = "partition"
+	CASE         = "case"
+	WHEN         = "when"
+	THEN         = "then"
+	END          = "end"
+	ASC          = "asc"
+	DESC         = "desc"
+	MERGE        = "merge"
+	MATCHED      = "matched"
+	TARGET       = "target"
+	SOURCE       = "source"
+)
+
+// ddl keyword
+const (
+	CREATE              = "create"
+	TABLE               = "table"
+	TEMPORARY           = "temporary"
+	IF                  = "if"
+	EXISTS              = "exists"
+	IFNOTEXISTS         = "if not exists"
+	PRIMARY             = "primary"
+	PRIMARYKEY          = "primary key"
+	FOREIGN             = "foreign"
+	FOREIGNKEY          = "foreign key"
+	UNIQUE              = "unique"
+	KEY                 = "key"
+	INDEX               = "index"
+	FULLTEXT            = "fulltext"
+	SPATIAL             = "spatial"
+	CONSTRAINT          = "constraint"
+	CHECK               = "check"
+	REFERENCES          = "references"
+	DEFAULT             = "default"
+	NULL                = "null"
+	NOTNULL             = "not null"
+	COMMENT             = "comment"
+	AUTOINCREMENT       = "auto_increment"
+	AUTOINCREMENTSQLITE = "autoincrement"
+	ENGINE              = "engine"
+	CHARSET             = "charset"
+	CHARACTER           = "character"
+	COLLATE             = "collate"
+	PARTITIONBY         = "partition by"
+	USING               = "using"
+	ALTER               = "alter"
+	ADD                 = "add"
+	DROP                = "drop"
+	MODIFY              = "modify"
+	CHANGE              = "change"
+	RENAME              = "rename"
+	COLUMN              = "column"
+	TO                  = "to"
+	FIRST               = "first"
+	AFTER               = "after"
+	TRUNCATE            = "truncate"
+	CASCADE             = "cascade"
+	RESTRICT            = "restrict"
+	CONCURRENTLY        = "concurrently"
+	VIEW                = "view"
+	MATERIALIZED        = "materialized"
+	WITH                = "with"
+	FUNCTION            = "function"
+	PROCEDURE           = "procedure"
+	TRIGGER             = "trigger"
+	RETURNS             = "returns"
+	LANGUAGE            = "language"
+	BEGIN               = "begin"
+	BEFORE              = "before"
+	INSTEAD             = "instead"
+	EACH                = "each"
+	EXECUTE             = "execute"
+	OF                  = "of"
+)
+
+// utility keyword
+const (
+	EXPLAIN     = "explain"
+	FOR         = "for"
+	ANALYZE     = "analyze"
+	USE         = "use"
+	SHOW        = "show"
+	GRANT       = "grant"
+	REVOKE      = "revoke"
+	START       = "start"
+	TRANSACTION = "transaction"
+	COMMIT      = "commit"
+	ROLLBACK    = "rollback"
+	SAVEPOINT   = "savepoint"
+	RELEASE     = "release"
+	DECLARE     = "declare"
+	OUTPUT      = "output"
+	RETURNING   = "returning"
 )
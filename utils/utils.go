@@ -2,8 +2,11 @@ package utils
 
 import (
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/go-xuan/sqlx/consts"
 )
@@ -17,7 +20,7 @@ func ExtractAlias(sql string) string {
 	sql = strings.TrimSpace(sql)
 	if index := IndexOfKeywordFirst(sql, consts.AS); index >= 0 {
 		return sql[index+3:]
-	} else if index = IndexOfString(sql, consts.Blank); index >= 0 {
+	} else if index = IndexExcludeQuotes(sql, consts.Blank, 0); index >= 0 {
 		return sql[index+1:]
 	} else {
 		return sql
@@ -26,18 +29,46 @@ func ExtractAlias(sql string) string {
 
 // ParseValuesInSql 解析sql中的变量值
 func ParseValuesInSql(sql string) (string, *strings.Replacer) {
-	values := regexp.MustCompile(`'[^']*'`).FindAllString(sql, -1)
-	if len(values) > 0 {
-		var all []string
-		for i, value := range values {
-			var replaceKey = consts.ReplacePrefix + strconv.Itoa(i+1) + consts.ReplaceSuffix
-			sql = strings.Replace(sql, value, replaceKey, 1)
-			all = append(all, replaceKey, value)
+	if sql, oldnew := ExtractValuesInSql(sql); len(oldnew) > 0 {
+		return sql, strings.NewReplacer(oldnew...)
+	} else {
+		return sql, nil
+	}
+}
+
+// ExtractValuesInSql 提取sql中的变量值，返回替换后的sql以及还原用的替换对（占位 -> 变量值）
+func ExtractValuesInSql(sql string) (string, []string) {
+	var oldnew []string
+	// postgresql美元符引用字符串（$$...$$、$tag$...$tag$），内部可能包含单引号，需要优先提取
+	for _, value := range dollarQuotedValues(sql) {
+		var replaceKey = consts.ReplacePrefix + strconv.Itoa(len(oldnew)/2+1) + consts.ReplaceSuffix
+		sql = strings.Replace(sql, value, replaceKey, 1)
+		oldnew = append(oldnew, replaceKey, value)
+	}
+	for _, value := range regexp.MustCompile(`'[^']*'`).FindAllString(sql, -1) {
+		var replaceKey = consts.ReplacePrefix + strconv.Itoa(len(oldnew)/2+1) + consts.ReplaceSuffix
+		sql = strings.Replace(sql, value, replaceKey, 1)
+		oldnew = append(oldnew, replaceKey, value)
+	}
+	return sql, oldnew
+}
+
+// 获取sql中所有的美元符引用字符串，排除单引号内的内容
+func dollarQuotedValues(sql string) []string {
+	var values []string
+	for i := 0; i < len(sql); i++ {
+		if c := sql[i]; c == '\'' {
+			if end := strings.IndexByte(sql[i+1:], c); end >= 0 {
+				i += end + 1
+			}
+		} else if tag := DollarQuoteTag(sql[i:]); c == '$' && tag != "" && (i == 0 || !isWordByte(sql[i-1])) {
+			if end := strings.Index(sql[i+len(tag):], tag); end >= 0 {
+				values = append(values, sql[i:i+len(tag)+end+len(tag)])
+				i += len(tag) + end + len(tag) - 1
+			}
 		}
-		replacer := strings.NewReplacer(all...)
-		return sql, replacer
 	}
-	return sql, nil
+	return values
 }
 
 // AllKeywordsToLower 将所有关键字转为小写
@@ -62,6 +93,92 @@ func AllKeywordsToLower(sql string) string {
 	return sql
 }
 
+// KeywordsToLower 将关键字（不区分大小写，Select、SELECT）转为小写，排除引号内的内容以及占位符、限定名中的单词（:NAME、@P1、t.KEY）
+func KeywordsToLower(sql string, isKeyword func(word string) bool) string {
+	var builder = strings.Builder{}
+	var offset int
+	for i := 0; i < len(sql); i++ {
+		if end := quoteEnd(sql, i); end > i {
+			i = end
+		} else if isWordByte(sql[i]) && (i == 0 || !isWordByte(sql[i-1]) && !strings.ContainsRune(":@$#{.", rune(sql[i-1]))) {
+			end = wordEnd(sql, i, false)
+			if word := sql[i:end]; word != strings.ToLower(word) && isKeyword(word) {
+				builder.WriteString(sql[offset:i])
+				builder.WriteString(strings.ToLower(word))
+				offset = end
+			}
+			i = end - 1
+		} else if isWordByte(sql[i]) {
+			i = wordEnd(sql, i, false) - 1
+		}
+	}
+	builder.WriteString(sql[offset:])
+	return builder.String()
+}
+
+// ConvertWords 逐个转换sql中的单词，排除引号、注释内的内容以及数字、占位符（:name、@p1、#{id}）和字符串前缀（N'a'、x'0F'），
+// qualified表示单词位于限定名中（t.name），call表示单词后紧跟左括号（count(）
+func ConvertWords(sql string, convert func(word string, qualified, call bool) string) string {
+	var builder = strings.Builder{}
+	var offset int
+	for i := 0; i < len(sql); i++ {
+		if end := quoteEnd(sql, i); end > i {
+			i = end
+		} else if end = commentEnd(sql, i); end > i {
+			i = end - 1
+		} else if isLetterByte(sql[i]) {
+			end = i
+			for end < len(sql) && (isWordByte(sql[end]) || sql[end] >= 0x80) {
+				end++
+			}
+			var prev, next byte
+			if i > 0 {
+				prev = sql[i-1]
+			}
+			if end < len(sql) {
+				next = sql[end]
+			}
+			if !isWordByte(prev) && prev < 0x80 && !strings.ContainsRune(":@$#{", rune(prev)) && next != '\'' {
+				var call = strings.HasPrefix(strings.TrimLeft(sql[end:], consts.Blank), consts.LeftBracket)
+				if word := convert(sql[i:end], prev == '.' || next == '.', call); word != sql[i:end] {
+					builder.WriteString(sql[offset:i])
+					builder.WriteString(word)
+					offset = end
+				}
+			}
+			i = end - 1
+		} else if isWordByte(sql[i]) || sql[i] >= 0x80 {
+			for i+1 < len(sql) && (isWordByte(sql[i+1]) || sql[i+1] >= 0x80) {
+				i++
+			}
+		}
+	}
+	builder.WriteString(sql[offset:])
+	return builder.String()
+}
+
+// 注释（--、/* */）结束位置，非注释时返回-1
+func commentEnd(sql string, i int) int {
+	switch {
+	case strings.HasPrefix(sql[i:], "--"):
+		if end := strings.IndexByte(sql[i:], '\n'); end > 0 {
+			return i + end
+		}
+		return len(sql)
+	case strings.HasPrefix(sql[i:], "/*"):
+		if end := strings.Index(sql[i+2:], "*/"); end >= 0 {
+			return i + 2 + end + 2
+		}
+		return len(sql)
+	}
+	return -1
+}
+
+// 是否字母或下划线（单词首字符）
+func isLetterByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
 func SplitValuesSql(sql string) []string {
 	sql = trimBrackets(sql)
 	values, value := SplitExcludeInBracket(sql, consts.Comma)
@@ -93,23 +210,60 @@ func SplitExcludeInBracket(sql, key string) ([]string, string) {
 				slice = append(slice, sql[offset:i])
 				offset = i + kl // 将当前拆分点后移一个sep长度
 			}
-		} else if sql[i:i+1] == consts.LeftBracket {
-			brackets++ // 括号加一
-		} else if sql[i:i+1] == consts.RightBracket && brackets > 0 {
+		} else if c := sql[i]; c == '(' || c == '[' {
+			brackets++ // 括号加一（包含数组下标以及数组构造，例如array[1,2]）
+		} else if (c == ')' || c == ']') && brackets > 0 {
 			brackets-- // 抵消一对括号
 		}
 	}
 	return slice, sql[offset:]
 }
 
+// SplitFieldsExcludeInBracket 根据空白字符进行拆分但是排除括号以及引号内的空白字符（`order items`）
+func SplitFieldsExcludeInBracket(sql string) []string {
+	var fields []string
+	var offset, brackets = -1, 0
+	for i := 0; i < len(sql); i++ {
+		switch c := sql[i]; c {
+		case '\'', '"', '`':
+			if offset < 0 {
+				offset = i
+			}
+			i = skipQuoted(sql, i, c)
+			continue
+		case ' ', '\t', '\n', '\r':
+			if brackets == 0 {
+				if offset >= 0 {
+					fields = append(fields, sql[offset:i])
+					offset = -1
+				}
+				continue
+			}
+		case '(', '[':
+			brackets++ // 括号加一
+		case ')', ']':
+			if brackets > 0 {
+				brackets-- // 抵消一对括号
+			}
+		}
+		if offset < 0 {
+			offset = i
+		}
+	}
+	if offset >= 0 {
+		fields = append(fields, sql[offset:])
+	}
+	return fields
+}
+
 // IndexExcludeBrackets 获取关键字下标但排除略括号内的关键字
 func IndexExcludeBrackets(sql, key string, pure bool) int {
 	var sl, kl, brackets = len(sql), len(key), 0
-	for i := 0; i < sl-kl; i++ {
+	for i := 0; i <= sl-kl; i++ {
 		if sql[i] == key[0] && sql[i:i+kl] == key {
 			// 当前位置前面的括号全部抵消才表示是有效命中
 			if brackets == 0 {
-				if pure && !HasAdjacent(sql, key, consts.Blank, i) {
+				if pure && sl > kl && !HasAdjacent(sql, key, consts.Blank, i) {
 					continue
 				}
 				return i
@@ -138,6 +292,17 @@ func ContainsKeywords(sql string, keys ...string) (string, int) {
 	return hit, index
 }
 
+// ContainsKeywordsExcludeBrackets 是否包含sql关键字，排除括号内（子查询）的关键字
+func ContainsKeywordsExcludeBrackets(sql string, keys ...string) (string, int) {
+	var hit, index = "", -1
+	for _, key := range keys {
+		if i := IndexExcludeBrackets(sql, key, true); i >= 0 && (index == -1 || i < index) {
+			hit, index = key, i
+		}
+	}
+	return hit, index
+}
+
 // LastIndexOfKeys 获取多个关键字中任一关键字最后命中下标
 func LastIndexOfKeys(sql string, keys ...string) (string, int) {
 	var hit, index = "", -1
@@ -384,3 +549,398 @@ func CutString(sql, str string, position ...int) (string, string) {
 	}
 	return sql, ""
 }
+
+// SplitStatements 根据分号拆分多条sql语句，排除引号、注释以及$$内的分号，支持mysql的delimiter命令切换分隔符
+func SplitStatements(sql string) []string {
+	var statements []string
+	for _, statement := range SplitScript(sql) {
+		statements = append(statements, statement.Text)
+	}
+	return statements
+}
+
+// Statement 脚本中拆分出的单条语句
+type Statement struct {
+	Text   string // 语句sql，不包含分隔符
+	Offset int    // 语句在脚本中的字节偏移
+	Batch  string // 语句之后的批处理分隔符（sql server：go、go 5），没有时为空
+
+	Delimiter string // 语句生效的结束分隔符（mysql：delimiter //），默认为分号
+}
+
+// SplitScript 拆分多语句sql脚本，拆分规则同SplitStatements，同时保留sql server的批处理分隔符以及mysql的delimiter命令
+func SplitScript(sql string) []*Statement {
+	var statements []*Statement
+	var offset, l, delimiter = 0, len(sql), consts.Semicolon
+	var flush = func(end int) {
+		if statement := strings.TrimSpace(sql[offset:end]); statement != "" {
+			start := end - len(strings.TrimLeftFunc(sql[offset:end], unicode.IsSpace))
+			statements = append(statements, &Statement{Text: statement, Offset: start, Delimiter: delimiter})
+		}
+	}
+	for i := 0; i < l; i++ {
+		switch c := sql[i]; {
+		case (c == 'd' || c == 'D') && lineStart(sql, i) && len(sql)-i > 10 && strings.EqualFold(sql[i:i+10], "delimiter "):
+			flush(i)
+			line := lineOf(sql, i)
+			if fields := strings.Fields(line[10:]); len(fields) > 0 {
+				delimiter = fields[0]
+			}
+			i += len(line)
+			offset = i + 1
+		case (c == 'g' || c == 'G') && lineStart(sql, i) && batchSeparator.MatchString(lineOf(sql, i)):
+			flush(i) // sql server批处理分隔符：go、go 10，归属于其之前的最后一条语句
+			if last := len(statements) - 1; last >= 0 && statements[last].Batch == "" {
+				statements[last].Batch = strings.Join(strings.Fields(lineOf(sql, i)), " ")
+			}
+			i += len(lineOf(sql, i))
+			offset = i + 1
+		case delimiter != consts.Semicolon && strings.HasPrefix(sql[i:], delimiter):
+			flush(i)
+			i += len(delimiter) - 1
+			offset = i + 1
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(sql, i, c)
+		case c == '-' && i+1 < l && sql[i+1] == '-':
+			if end := strings.IndexByte(sql[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = l
+			}
+		case c == '/' && i+1 < l && sql[i+1] == '*':
+			if end := strings.Index(sql[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = l
+			}
+		case c == '$':
+			if tag := DollarQuoteTag(sql[i:]); tag != "" {
+				if end := strings.Index(sql[i+len(tag):], tag); end >= 0 {
+					i += len(tag) + end + len(tag) - 1
+				} else {
+					i = l
+				}
+			}
+		case c == ';' && delimiter == consts.Semicolon:
+			flush(i)
+			offset = i + 1
+		}
+	}
+	if offset < l {
+		flush(l)
+	}
+	return statements
+}
+
+// sql server批处理分隔符
+var batchSeparator = regexp.MustCompile(`(?i)^go(\s+\d+)?\s*$`)
+
+// 获取从下标i开始到行尾的内容（不包含换行符）
+func lineOf(sql string, i int) string {
+	if end := strings.IndexByte(sql[i:], '\n'); end >= 0 {
+		return sql[i : i+end]
+	}
+	return sql[i:]
+}
+
+// 是否处于行首（仅有前置空白字符）
+func lineStart(sql string, i int) bool {
+	for j := i - 1; j >= 0; j-- {
+		switch sql[j] {
+		case '\n':
+			return true
+		case ' ', '\t', '\r':
+			continue
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// IndicesOfPlaceholders 获取sql中所有绑定占位符（?、$1、:name、@p1、#{id}、${table}）的下标范围，排除引号以及注释内的内容
+func IndicesOfPlaceholders(sql string) [][]int {
+	var indices [][]int
+	var l = len(sql)
+	for i := 0; i < l; i++ {
+		switch c := sql[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(sql, i, c)
+		case c == '-' && i+1 < l && sql[i+1] == '-':
+			if end := strings.IndexByte(sql[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = l
+			}
+		case c == '/' && i+1 < l && sql[i+1] == '*':
+			if end := strings.Index(sql[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = l
+			}
+		case c == '?' && i+1 < l && (sql[i+1] == '|' || sql[i+1] == '&'): // postgresql jsonb操作符：?|、?&
+			i++
+		case c == '?':
+			if !isQuestionOperator(sql, i) {
+				indices = append(indices, []int{i, i + 1})
+			}
+		case (c == '#' || c == '$') && i+1 < l && sql[i+1] == '{': // mybatis：#{id}、${table}
+			if end := strings.IndexByte(sql[i:], '}'); end > 0 {
+				indices = append(indices, []int{i, i + end + 1})
+				i += end
+			}
+		case c == '$':
+			if tag := DollarQuoteTag(sql[i:]); tag != "" {
+				if end := strings.Index(sql[i+len(tag):], tag); end >= 0 {
+					i += len(tag) + end + len(tag) - 1
+				} else {
+					i = l
+				}
+			} else if end := wordEnd(sql, i+1, true); end > i+1 && (i == 0 || !isWordByte(sql[i-1])) {
+				indices = append(indices, []int{i, end})
+				i = end - 1
+			}
+		case c == ':' || c == '@':
+			if i+1 < l && sql[i+1] == c { // 类型转换（::）、系统变量（@@）
+				i++
+			} else if end := wordEnd(sql, i+1, false); end > i+1 && (i == 0 || !isWordByte(sql[i-1])) {
+				indices = append(indices, []int{i, end})
+				i = end - 1
+			}
+		}
+	}
+	return indices
+}
+
+// 问号之前可以紧跟占位符的关键字
+var placeholderKeywords = map[string]bool{
+	"select": true, "where": true, "and": true, "or": true, "not": true, "case": true, "when": true, "then": true,
+	"else": true, "limit": true, "offset": true, "fetch": true, "first": true, "next": true, "top": true, "in": true,
+	"like": true, "ilike": true, "is": true, "by": true, "values": true, "set": true, "between": true, "on": true,
+	"having": true, "return": true, "distinct": true, "all": true, "any": true, "some": true, "exists": true,
+	"escape": true, "interval": true, "div": true, "mod": true, "regexp": true, "rlike": true,
+}
+
+// 问号是否postgresql的jsonb操作符（data ? 'k'）：问号前为操作数（标识符、右括号、引号），问号后也为操作数
+func isQuestionOperator(sql string, i int) bool {
+	var prev = i - 1
+	for ; prev >= 0 && isSpaceByte(sql[prev]); prev-- {
+	}
+	var next = i + 1
+	for ; next < len(sql) && isSpaceByte(sql[next]); next++ {
+	}
+	if prev < 0 || next >= len(sql) {
+		return false
+	}
+	switch c := sql[next]; {
+	case c == '\'' || c == '"' || c == '`' || c == '(' || c == '[' || isWordByte(c):
+	default:
+		return false
+	}
+	switch c := sql[prev]; {
+	case c == ')' || c == ']' || c == '\'' || c == '"' || c == '`':
+		return true
+	case isWordByte(c):
+		var start = prev
+		for ; start > 0 && isWordByte(sql[start-1]); start-- {
+		}
+		return !placeholderKeywords[strings.ToLower(sql[start:prev+1])]
+	default:
+		return false
+	}
+}
+
+// 获取从start开始的连续单词字符的结束下标
+func wordEnd(sql string, start int, digit bool) int {
+	var i = start
+	for ; i < len(sql); i++ {
+		if c := sql[i]; digit && !(c >= '0' && c <= '9') || !digit && !isWordByte(c) {
+			break
+		}
+	}
+	return i
+}
+
+// 是否空白字符
+func isSpaceByte(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\t' || c == '\r'
+}
+
+// 是否单词字符（字母、数字、下划线）
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_'
+}
+
+// IndexExcludeQuotes 获取str在sql中从start开始首次出现的下标，排除引号（'、"、`、[]）内的内容
+func IndexExcludeQuotes(sql, str string, start int) int {
+	for i := start; i < len(sql); i++ {
+		if end := quoteEnd(sql, i); end > i {
+			i = end
+		} else if strings.HasPrefix(sql[i:], str) {
+			return i
+		}
+	}
+	return -1
+}
+
+// LastIndexExcludeQuotes 获取str在sql中最后一次出现的下标，排除引号（'、"、`、[]）内的内容
+func LastIndexExcludeQuotes(sql, str string) int {
+	var last = -1
+	for i := 0; i < len(sql); i++ {
+		if end := quoteEnd(sql, i); end > i {
+			i = end
+		} else if strings.HasPrefix(sql[i:], str) {
+			last = i
+		}
+	}
+	return last
+}
+
+// SplitIdentifier 拆分限定标识符（catalog.schema.table、"my schema".`my table`），排除引号内的点号，非标识符时返回nil
+func SplitIdentifier(sql string) []string {
+	var parts []string
+	var offset int
+	for i := 0; i <= len(sql); i++ {
+		if i == len(sql) || sql[i] == '.' {
+			part := sql[offset:i]
+			if !identifierPart.MatchString(part) {
+				return nil
+			}
+			parts = append(parts, part)
+			offset = i + 1
+		} else if end := quoteEnd(sql, i); end > i {
+			i = end
+		}
+	}
+	return parts
+}
+
+var identifierPart = regexp.MustCompile("^([A-Za-z_#@][\\w$#@]*|\\*|\"[^\"]+\"|`[^`]+`|\\[[^\\]]+])$")
+
+// UnquoteIdentifier 去除标识符引号，返回标识符以及引号（`、"、[），未引用时引号为空
+func UnquoteIdentifier(sql string) (string, string) {
+	if l := len(sql); l >= 2 {
+		switch first, last := sql[0], sql[l-1]; {
+		case first == '"' && last == '"', first == '`' && last == '`', first == '[' && last == ']':
+			return sql[1 : l-1], sql[:1]
+		}
+	}
+	return sql, ""
+}
+
+// QuoteIdentifier 使用引号（`、"、[）引用标识符
+func QuoteIdentifier(name, quote string) string {
+	switch quote {
+	case "":
+		return name
+	case "[":
+		return "[" + name + "]"
+	default:
+		return quote + name + quote
+	}
+}
+
+// QuoteEnd 下标i处为引号（'、"、`、[）起始时返回闭合引号下标，否则返回-1
+func QuoteEnd(sql string, i int) int {
+	return quoteEnd(sql, i)
+}
+
+// 引号起始时返回闭合引号下标，否则返回-1
+func quoteEnd(sql string, i int) int {
+	switch c := sql[i]; c {
+	case '\'', '"', '`':
+		return skipQuoted(sql, i, c)
+	case '[': // 排除数组下标，例如arr[1]
+		if i == 0 || !isWordByte(sql[i-1]) && sql[i-1] != ')' && sql[i-1] != ']' {
+			if end := strings.IndexByte(sql[i:], ']'); end > 0 {
+				return i + end
+			}
+		}
+	}
+	return -1
+}
+
+// DollarQuoteTag 获取字符串开头的postgresql美元符引用标记，例如$$、$body$
+func DollarQuoteTag(sql string) string {
+	if len(sql) < 2 || sql[0] != '$' {
+		return ""
+	}
+	for i := 1; i < len(sql); i++ {
+		if c := sql[i]; c == '$' {
+			return sql[:i+1]
+		} else if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || i > 1 && c >= '0' && c <= '9') {
+			return ""
+		}
+	}
+	return ""
+}
+
+// 跳过引号内容，返回闭合引号下标（两个连续引号或者反斜杠视为转义）
+func skipQuoted(sql string, start int, quote byte) int {
+	for i := start + 1; i < len(sql); i++ {
+		if sql[i] == '\\' && quote == '\'' {
+			i++
+		} else if sql[i] == quote {
+			if i+1 < len(sql) && sql[i+1] == quote {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return len(sql)
+}
+
+// 东亚宽字符（East Asian Width为W或F）区间，终端中占两列
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F}, {0x231A, 0x231B}, {0x2329, 0x232A}, {0x23E9, 0x23EC}, {0x23F0, 0x23F0}, {0x23F3, 0x23F3},
+	{0x25FD, 0x25FE}, {0x2614, 0x2615}, {0x2648, 0x2653}, {0x267F, 0x267F}, {0x2693, 0x2693}, {0x26A1, 0x26A1},
+	{0x26AA, 0x26AB}, {0x26BD, 0x26BE}, {0x26C4, 0x26C5}, {0x26CE, 0x26CE}, {0x26D4, 0x26D4}, {0x26EA, 0x26EA},
+	{0x26F2, 0x26F3}, {0x26F5, 0x26F5}, {0x26FA, 0x26FA}, {0x26FD, 0x26FD}, {0x2705, 0x2705}, {0x270A, 0x270B},
+	{0x2728, 0x2728}, {0x274C, 0x274C}, {0x274E, 0x274E}, {0x2753, 0x2755}, {0x2757, 0x2757}, {0x2795, 0x2797},
+	{0x27B0, 0x27B0}, {0x27BF, 0x27BF}, {0x2B1B, 0x2B1C}, {0x2B50, 0x2B50}, {0x2B55, 0x2B55},
+	{0x2E80, 0x303E},   // 部首、康熙部首、全角空格以及中文标点（、。「」）
+	{0x3041, 0x33FF},   // 平假名、片假名、注音、兼容字符
+	{0x3400, 0x4DBF},   // 中日韩统一表意文字扩展A
+	{0x4E00, 0x9FFF},   // 中日韩统一表意文字
+	{0xA000, 0xA4CF},   // 彝文
+	{0xA960, 0xA97F},   // 谚文字母扩展A
+	{0xAC00, 0xD7A3},   // 谚文音节
+	{0xF900, 0xFAFF},   // 中日韩兼容表意文字
+	{0xFE10, 0xFE19},   // 竖排标点
+	{0xFE30, 0xFE6F},   // 中日韩兼容形式、小写变体
+	{0xFF00, 0xFF60},   // 全角ASCII以及全角标点（，（）：；！？）
+	{0xFFE0, 0xFFE6},   // 全角符号（￥、￡）
+	{0x1F300, 0x1F64F}, // 表情符号
+	{0x1F900, 0x1F9FF}, // 补充表情符号
+	{0x20000, 0x2FFFD}, // 中日韩统一表意文字扩展B及以后
+	{0x30000, 0x3FFFD},
+}
+
+// DisplayWidth 字符串在终端中的显示宽度：东亚宽字符以及全角字符占两列，组合字符以及零宽字符不占列，其余占一列
+func DisplayWidth(str string) int {
+	var width int
+	for i := 0; i < len(str); {
+		if c := str[i]; c < utf8.RuneSelf { // ASCII
+			width, i = width+1, i+1
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(str[i:])
+		width, i = width+RuneWidth(r), i+size
+	}
+	return width
+}
+
+// RuneWidth 字符在终端中的显示宽度
+func RuneWidth(r rune) int {
+	switch {
+	case r < utf8.RuneSelf:
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me) || r >= 0x200B && r <= 0x200F || r == 0x2060 || r == 0xFEFF:
+		return 0
+	}
+	if i := sort.Search(len(wideRanges), func(i int) bool { return wideRanges[i][1] >= r }); i < len(wideRanges) && r >= wideRanges[i][0] {
+		return 2
+	}
+	return 1
+}
@@ -42,3 +42,111 @@ func TestTrimBrackets(t *testing.T) {
 	sql = trimBrackets(sql)
 	fmt.Println(sql)
 }
+
+func TestSplitStatements(t *testing.T) {
+	sql := "select ';' from a; -- x;y\nupdate b set c = 'it''s;'; /* ; */ select $$ a; b $$;"
+	statements := SplitStatements(sql)
+	fmt.Println(statements)
+	if len(statements) != 3 {
+		t.Errorf("unexpected statements %d", len(statements))
+	}
+}
+
+func TestSplitStatementsWithDelimiter(t *testing.T) {
+	sql := "delimiter //\ncreate procedure p() begin select 1; select 2; end //\ndelimiter ;\ncall p();"
+	statements := SplitStatements(sql)
+	fmt.Println(statements)
+	if len(statements) != 2 || statements[1] != "call p()" {
+		t.Errorf("unexpected statements %q", statements)
+	}
+	if script := SplitScript(sql); script[0].Delimiter != "//" || script[1].Delimiter != ";" {
+		t.Errorf("unexpected delimiters %+v", script)
+	}
+}
+
+func TestSplitStatementsWithGo(t *testing.T) {
+	statements := SplitStatements("select 1\nGO\nselect 'go'\n  go 2\nselect good from t")
+	fmt.Println(statements)
+	if len(statements) != 3 || statements[2] != "select good from t" {
+		t.Errorf("unexpected statements %q", statements)
+	}
+	script := SplitScript("select 1\nGO\nselect 2\n  go 2  \nselect 3")
+	if len(script) != 3 || script[0].Batch != "GO" || script[1].Batch != "go 2" || script[2].Batch != "" {
+		t.Errorf("unexpected batches %+v", script)
+	}
+	if script[1].Offset != 12 || script[2].Offset != 30 {
+		t.Errorf("unexpected offsets %d, %d", script[1].Offset, script[2].Offset)
+	}
+}
+
+func TestIndicesOfPlaceholders(t *testing.T) {
+	sql := "select a::int, ':x', @@y from t where a = ? and b = $2 and c = :c and d = @d and e = #{e} -- ?\n and f := 1"
+	var texts []string
+	for _, index := range IndicesOfPlaceholders(sql) {
+		texts = append(texts, sql[index[0]:index[1]])
+	}
+	fmt.Println(texts)
+	if len(texts) != 5 {
+		t.Errorf("unexpected placeholders %v", texts)
+	}
+	if indices := IndicesOfPlaceholders("select ? from t where data ? 'k' and tags ?| array['a'] and (x) ?& y and id = $1 limit ?"); len(indices) != 3 {
+		t.Errorf("unexpected placeholders %v", indices)
+	}
+}
+
+func TestSplitIdentifier(t *testing.T) {
+	parts := SplitIdentifier("`my db`.\"a.b\".[T x].col")
+	fmt.Println(parts)
+	if len(parts) != 4 {
+		t.Errorf("unexpected parts %v", parts)
+	}
+	if parts = SplitIdentifier("count(a.b)"); parts != nil {
+		t.Errorf("unexpected parts %v", parts)
+	}
+	if index := IndexExcludeQuotes(`"order items" oi`, " ", 0); index != 13 {
+		t.Errorf("unexpected index %d", index)
+	}
+}
+
+func TestKeywordsToLower(t *testing.T) {
+	var isKeyword = func(word string) bool {
+		switch strings.ToLower(word) {
+		case "select", "from", "key", "where":
+			return true
+		}
+		return false
+	}
+	sql := KeywordsToLower("SELECT KEY, 'FROM', `FROM`, t.KEY, :KEY, Select FROM t WHERE a = 1", isKeyword)
+	fmt.Println(sql)
+	if sql != "select key, 'FROM', `FROM`, t.KEY, :KEY, select from t where a = 1" {
+		t.Errorf("unexpected sql %s", sql)
+	}
+}
+
+func TestConvertWords(t *testing.T) {
+	sql := ConvertWords("select Count(*), t.Name, 'Text', `Col`, N'abc', :Id, 1e5 from T -- Note\n/* Hint */ where 名称Ab = 1", func(word string, qualified, call bool) string {
+		switch {
+		case qualified:
+			return "Q" + word
+		case call:
+			return "C" + word
+		default:
+			return strings.ToUpper(word)
+		}
+	})
+	fmt.Println(sql)
+	if sql != "SELECT CCount(*), Qt.QName, 'Text', `Col`, N'abc', :Id, 1e5 FROM T -- Note\n/* Hint */ WHERE 名称Ab = 1" {
+		t.Errorf("unexpected sql %s", sql)
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	var cases = map[string]int{
+		"name": 4, "姓名": 4, "用户，名称（全角）": 18, "ｓｑｌ": 6, "café": 4, "é": 1, "한국어": 6, "カタカナ": 8, "「」、。": 8, "": 0,
+	}
+	for str, expect := range cases {
+		if width := DisplayWidth(str); width != expect {
+			t.Errorf("unexpected width of %q: %d, expect %d", str, width, expect)
+		}
+	}
+}